@@ -0,0 +1,444 @@
+package gsr7
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+//region Interface
+
+// ChunkExtension is a name and an optional value sent along with the size of a chunk in the chunked transfer coding.
+//
+// See https://datatracker.ietf.org/doc/html/rfc9112#section-7.1.1 for details.
+type ChunkExtension struct {
+	Name  string
+	Value string
+}
+
+// ChunkedReadableStream is a ReadableStream that removes the chunked transfer coding from the data read from an
+// underlying reader. The data is decoded as it is read, and the decoded data is retained so the stream can be seeked.
+//
+// The size of each chunk is parsed strictly: it must consist of hexadecimal digits only and fit into an int64, and both
+// the chunk size line and the chunk data must be followed by CRLF immediately. The lines of the trailer section must
+// be terminated by CRLF as well. Violations result in a *ParseError wrapping ErrMalformedChunk or ErrMalformedHeader,
+// which is returned by every further Read call.
+//
+// See https://datatracker.ietf.org/doc/html/rfc9112#section-7.1 for details.
+type ChunkedReadableStream interface {
+	ReadableStream
+
+	// GetTrailers returns the fields of the trailer section. The trailers are empty until Read has returned io.EOF
+	// or the full content was retrieved with Bytes or String.
+	GetTrailers() Headers
+	// GetChunkExtensions returns the extensions of all chunks read so far in order, including the extensions of the
+	// last chunk once the stream has reached EOF. Since they are retained, their names and values count against the
+	// maximum body size.
+	GetChunkExtensions() []ChunkExtension
+}
+
+// ChunkedWritableStream is a WritableStream that applies the chunked transfer coding to the data written to an
+// underlying writer. Every non-empty Write call results in one chunk.
+//
+// See https://datatracker.ietf.org/doc/html/rfc9112#section-7.1 for details.
+type ChunkedWritableStream interface {
+	WritableStream
+
+	// WriteChunk writes the data as a single chunk with the specified extensions. If the data is empty, an extension
+	// is invalid, or the stream is closed, an error is returned.
+	WriteChunk(data []byte, extensions ...ChunkExtension) error
+	// CloseWithTrailers writes the last chunk followed by the trailer section. Close is equivalent to calling
+	// CloseWithTrailers with an empty header collection. The underlying writer is not closed. If a field is not
+	// allowed in trailers, an error is returned and the stream stays open.
+	CloseWithTrailers(trailers Headers) error
+}
+
+// NewChunkedReadableStream creates a ChunkedReadableStream that decodes the data read from r. The limits restrict
+// the length of the chunk size lines and trailer lines, the number of trailer fields and the size of the decoded
+// body including the chunk extensions. The offsets in parse errors are relative to the start of the chunked data.
+//
+// If r is a *bufio.Reader, it is used directly, so it is left at the end of the trailer section once the stream has
+// reached EOF. Otherwise, data following the chunked body may be consumed from r.
+func NewChunkedReadableStream(r io.Reader, limits ParseLimits) ChunkedReadableStream {
+	br, ok := r.(*bufio.Reader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+	return newChunkedReadableStream(&wireReader{r: br, limits: limits.withDefaults()})
+}
+
+// NewChunkedWritableStream creates a ChunkedWritableStream that writes the encoded data to w.
+func NewChunkedWritableStream(w io.Writer) ChunkedWritableStream {
+	return &chunkedWritableStream{w: w}
+}
+
+//endregion
+
+//region Implementation
+
+// chunkedReadSize is the maximum amount of chunk data decoded in one step, so large chunks are not read at once.
+const chunkedReadSize = 32 * 1024
+
+type chunkedReadableStream struct {
+	lock     sync.Mutex
+	w        *wireReader
+	data     []byte
+	position int64
+	// remaining is the number of bytes of the current chunk that have not been decoded yet.
+	remaining int64
+	done      bool
+	// eof is true once the body was read to the end, which makes the trailers available.
+	eof        bool
+	err        error
+	extensions []ChunkExtension
+	// extensionSize is the total length of the names and values in extensions.
+	extensionSize int64
+	trailers      Headers
+}
+
+// newChunkedReadableStream creates a stream decoding from a wire reader, which may be shared with the parser of the
+// surrounding message so the offsets in errors are relative to the start of the message.
+func newChunkedReadableStream(w *wireReader) *chunkedReadableStream {
+	return &chunkedReadableStream{w: w}
+}
+
+// decode decodes the next part of the body. Errors are retained and returned by every further call.
+func (s *chunkedReadableStream) decode() error {
+	if s.err != nil || s.done {
+		return s.err
+	}
+	s.err = s.decodeNext()
+	return s.err
+}
+
+func (s *chunkedReadableStream) decodeNext() error {
+	if s.remaining == 0 {
+		line, start, err := s.w.readCRLFLine(ErrMalformedChunk)
+		if err == io.EOF {
+			return &ParseError{Offset: start, Err: io.ErrUnexpectedEOF}
+		}
+		if err != nil {
+			return err
+		}
+		size, extensions, err := parseChunkSizeLine(line)
+		if err != nil {
+			return &ParseError{Offset: start, Err: err}
+		}
+		for _, extension := range extensions {
+			s.extensionSize += int64(len(extension.Name) + len(extension.Value))
+		}
+		if s.w.limits.MaxBodySize >= 0 && s.extensionSize > s.w.limits.MaxBodySize-int64(len(s.data)) {
+			return parseErrorf(
+				start, ErrBodyTooLarge, "the body and the chunk extensions exceed %d bytes", s.w.limits.MaxBodySize,
+			)
+		}
+		s.extensions = append(s.extensions, extensions...)
+		if size == 0 {
+			trailers, err := s.w.readFields(true, true)
+			if err != nil {
+				return err
+			}
+			s.trailers = trailers
+			s.done = true
+			return nil
+		}
+		if s.w.limits.MaxBodySize >= 0 && size > s.w.limits.MaxBodySize-int64(len(s.data))-s.extensionSize {
+			return parseErrorf(start, ErrBodyTooLarge, "the body exceeds %d bytes", s.w.limits.MaxBodySize)
+		}
+		s.remaining = size
+	}
+	n := s.remaining
+	if n > chunkedReadSize {
+		n = chunkedReadSize
+	}
+	length := len(s.data)
+	s.data = append(s.data, make([]byte, n)...)
+	read, err := io.ReadFull(s.w.r, s.data[length:])
+	s.w.offset += int64(read)
+	if err != nil {
+		s.data = s.data[:length+read]
+		return &ParseError{Offset: s.w.offset, Err: io.ErrUnexpectedEOF}
+	}
+	s.remaining -= n
+	if s.remaining > 0 {
+		return nil
+	}
+	line, start, err := s.w.readCRLFLine(ErrMalformedChunk)
+	if err == io.EOF {
+		return &ParseError{Offset: start, Err: io.ErrUnexpectedEOF}
+	}
+	if err != nil {
+		return err
+	}
+	if line != "" {
+		return parseErrorf(start, ErrMalformedChunk, "the chunk data is longer than the chunk size")
+	}
+	return nil
+}
+
+// decodeAll decodes the rest of the body.
+func (s *chunkedReadableStream) decodeAll() error {
+	for !s.done {
+		if err := s.decode(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *chunkedReadableStream) Read(p []byte) (int, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if len(p) == 0 {
+		return 0, nil
+	}
+	for s.position >= int64(len(s.data)) {
+		if s.done {
+			s.eof = true
+			return 0, io.EOF
+		}
+		// Data decoded before an error is returned first, the error is returned by the next call.
+		if err := s.decode(); err != nil && s.position >= int64(len(s.data)) {
+			return 0, err
+		}
+	}
+	n := copy(p, s.data[s.position:])
+	s.position += int64(n)
+	return n, nil
+}
+
+func (s *chunkedReadableStream) Seek(offset int64, whence int) (int64, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	var position int64
+	switch whence {
+	case io.SeekStart:
+		position = offset
+	case io.SeekCurrent:
+		position = s.position + offset
+	case io.SeekEnd:
+		if err := s.decodeAll(); err != nil {
+			return s.position, err
+		}
+		position = int64(len(s.data)) + offset
+	default:
+		return s.position, fmt.Errorf("invalid whence: %d", whence)
+	}
+	if position < 0 {
+		return s.position, errors.New("negative position")
+	}
+	s.position = position
+	return position, nil
+}
+
+func (s *chunkedReadableStream) Close() error {
+	return nil
+}
+
+// Bytes decodes the rest of the body. If the body is malformed, the data decoded up to the error is returned.
+func (s *chunkedReadableStream) Bytes() []byte {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.decodeAll() == nil {
+		s.eof = true
+	}
+	result := make([]byte, len(s.data))
+	copy(result, s.data)
+	return result
+}
+
+func (s *chunkedReadableStream) String() string {
+	return string(s.Bytes())
+}
+
+func (s *chunkedReadableStream) GetTrailers() Headers {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if !s.eof {
+		return NewHeaders()
+	}
+	return s.trailers
+}
+
+func (s *chunkedReadableStream) GetChunkExtensions() []ChunkExtension {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	result := make([]ChunkExtension, len(s.extensions))
+	copy(result, s.extensions)
+	return result
+}
+
+// parseChunkSizeLine parses the size of a chunk and its extensions. The size must be a hexadecimal number that fits
+// into an int64.
+func parseChunkSizeLine(line string) (int64, []ChunkExtension, error) {
+	sizeString, extensionString := line, ""
+	if i := strings.IndexByte(line, ';'); i >= 0 {
+		sizeString, extensionString = strings.TrimRight(line[:i], " \t"), line[i:]
+	}
+	if sizeString == "" {
+		return 0, nil, fmt.Errorf("%w: missing chunk size", ErrMalformedChunk)
+	}
+	size := int64(0)
+	for i := 0; i < len(sizeString); i++ {
+		digit, ok := hexDigitValue(sizeString[i])
+		if !ok {
+			return 0, nil, fmt.Errorf("%w: invalid chunk size %q", ErrMalformedChunk, sizeString)
+		}
+		if size > (1<<63-1)>>4 {
+			return 0, nil, fmt.Errorf("%w: chunk size %s overflows", ErrMalformedChunk, sizeString)
+		}
+		size = size<<4 | int64(digit)
+	}
+	extensions, err := parseChunkExtensions(extensionString)
+	if err != nil {
+		return 0, nil, err
+	}
+	return size, extensions, nil
+}
+
+func hexDigitValue(c byte) (byte, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return c - '0', true
+	case c >= 'a' && c <= 'f':
+		return c - 'a' + 10, true
+	case c >= 'A' && c <= 'F':
+		return c - 'A' + 10, true
+	}
+	return 0, false
+}
+
+// parseChunkExtensions parses the chunk extensions following the chunk size, starting with the first semicolon. The
+// values of quoted strings are unescaped.
+//
+// See https://datatracker.ietf.org/doc/html/rfc9112#section-7.1.1 for details.
+func parseChunkExtensions(s string) ([]ChunkExtension, error) {
+	var extensions []ChunkExtension
+	for s != "" {
+		if s[0] != ';' {
+			return nil, fmt.Errorf("%w: invalid chunk extension %q", ErrMalformedChunk, s)
+		}
+		s = strings.TrimLeft(s[1:], " \t")
+		name := s[:tokenLength(s)]
+		if name == "" {
+			return nil, fmt.Errorf("%w: missing chunk extension name", ErrMalformedChunk)
+		}
+		s = strings.TrimLeft(s[len(name):], " \t")
+		value := ""
+		if strings.HasPrefix(s, "=") {
+			s = strings.TrimLeft(s[1:], " \t")
+			if strings.HasPrefix(s, "\"") {
+				unquoted, rest, ok := parseQuotedString(s)
+				if !ok {
+					return nil, fmt.Errorf("%w: invalid quoted string in chunk extension %s", ErrMalformedChunk, name)
+				}
+				value, s = unquoted, rest
+			} else {
+				value = s[:tokenLength(s)]
+				if value == "" {
+					return nil, fmt.Errorf("%w: missing value of chunk extension %s", ErrMalformedChunk, name)
+				}
+				s = s[len(value):]
+			}
+			s = strings.TrimLeft(s, " \t")
+		}
+		extensions = append(extensions, ChunkExtension{Name: name, Value: value})
+	}
+	return extensions, nil
+}
+
+type chunkedWritableStream struct {
+	lock   sync.Mutex
+	w      io.Writer
+	closed bool
+}
+
+func (s *chunkedWritableStream) Write(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if err := s.WriteChunk(p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (s *chunkedWritableStream) WriteChunk(data []byte, extensions ...ChunkExtension) error {
+	if len(data) == 0 {
+		return errors.New("empty chunks cannot be written, the last chunk is written by Close")
+	}
+	if err := validate(validateChunkExtensions(extensions)); err != nil {
+		return err
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.closed {
+		return errors.New("write to a closed chunked stream")
+	}
+	sizeLine := strconv.FormatInt(int64(len(data)), 16) + formatChunkExtensions(extensions) + "\r\n"
+	if _, err := io.WriteString(s.w, sizeLine); err != nil {
+		return err
+	}
+	if _, err := s.w.Write(data); err != nil {
+		return err
+	}
+	_, err := io.WriteString(s.w, "\r\n")
+	return err
+}
+
+func (s *chunkedWritableStream) Close() error {
+	return s.CloseWithTrailers(NewHeaders())
+}
+
+func (s *chunkedWritableStream) CloseWithTrailers(trailers Headers) error {
+	for _, field := range trailers.GetHeaders() {
+		if err := validate(validateTrailerName(field[0])); err != nil {
+			return err
+		}
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.closed {
+		return errors.New("the chunked stream is already closed")
+	}
+	s.closed = true
+	var b strings.Builder
+	b.WriteString("0\r\n")
+	for _, field := range trailers.GetHeaders() {
+		for _, value := range field[1:] {
+			b.WriteString(field[0] + ": " + value + "\r\n")
+		}
+	}
+	b.WriteString("\r\n")
+	_, err := io.WriteString(s.w, b.String())
+	return err
+}
+
+// formatChunkExtensions formats the extensions for a chunk size line. Values that are not tokens are quoted.
+func formatChunkExtensions(extensions []ChunkExtension) string {
+	var b strings.Builder
+	for _, extension := range extensions {
+		b.WriteString(";" + extension.Name)
+		switch {
+		case extension.Value == "":
+		case tokenLength(extension.Value) == len(extension.Value):
+			b.WriteString("=" + extension.Value)
+		default:
+			b.WriteString("=\"")
+			for i := 0; i < len(extension.Value); i++ {
+				if c := extension.Value[i]; c == '"' || c == '\\' {
+					b.WriteByte('\\')
+				}
+				b.WriteByte(extension.Value[i])
+			}
+			b.WriteString("\"")
+		}
+	}
+	return b.String()
+}
+
+//endregion
@@ -0,0 +1,155 @@
+package gsr7_test
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"go.debugged.it/gsr7"
+)
+
+//region Examples
+
+func ExampleNewChunkedReadableStream() {
+	input := "5\r\nhello\r\n6;ext=\"a b\"\r\n world\r\n0\r\nDigest: abc\r\n\r\n"
+	stream := gsr7.NewChunkedReadableStream(strings.NewReader(input), gsr7.DefaultParseLimits())
+	body, err := io.ReadAll(stream)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(string(body))
+	fmt.Println(stream.GetChunkExtensions())
+	fmt.Println(stream.GetTrailers().GetHeaderLine("Digest"))
+	// Output: hello world
+	// [{ext a b}]
+	// abc
+}
+
+func ExampleNewChunkedWritableStream() {
+	var output bytes.Buffer
+	stream := gsr7.NewChunkedWritableStream(&output)
+	_, _ = stream.Write([]byte("hello"))
+	_ = stream.WriteChunk([]byte(" world"), gsr7.ChunkExtension{Name: "ext", Value: "a b"})
+	_ = stream.CloseWithTrailers(gsr7.NewHeaders().WithHeader("Digest", "abc"))
+	fmt.Printf("%q\n", output.String())
+	// Output: "5\r\nhello\r\n6;ext=\"a b\"\r\n world\r\n0\r\nDigest: abc\r\n\r\n"
+}
+
+//endregion
+
+//region Tests
+
+func TestChunkedReadableStreamErrors(t *testing.T) {
+	testData := []struct {
+		name   string
+		input  string
+		kind   error
+		offset int64
+	}{
+		{"overflow", "10000000000000000\r\n", gsr7.ErrMalformedChunk, 0},
+		{"hex prefix", "0x5\r\nhello\r\n0\r\n\r\n", gsr7.ErrMalformedChunk, 0},
+		{"sign", "+5\r\nhello\r\n0\r\n\r\n", gsr7.ErrMalformedChunk, 0},
+		{"leading whitespace", " 5\r\nhello\r\n0\r\n\r\n", gsr7.ErrMalformedChunk, 0},
+		{"trailing whitespace", "5 \r\nhello\r\n0\r\n\r\n", gsr7.ErrMalformedChunk, 0},
+		{"missing size", ";a=b\r\n", gsr7.ErrMalformedChunk, 0},
+		{"invalid extension", "5;=b\r\nhello\r\n0\r\n\r\n", gsr7.ErrMalformedChunk, 0},
+		{"data too long", "3\r\nhello\r\n0\r\n\r\n", gsr7.ErrMalformedChunk, 6},
+		{"bare CR", "5\rX\r\nhello\r\n0\r\n\r\n", gsr7.ErrMalformedChunk, 1},
+		{"bare LF after chunk size", "5\nhello\r\n0\r\n\r\n", gsr7.ErrMalformedChunk, 1},
+		{"bare LF after chunk data", "5\r\nhello\n0\r\n\r\n", gsr7.ErrMalformedChunk, 8},
+		{"bare LF after last chunk", "5\r\nhello\r\n0\n\r\n", gsr7.ErrMalformedChunk, 11},
+		{"truncated data", "5\r\nhel", io.ErrUnexpectedEOF, 6},
+		{"missing last chunk", "5\r\nhello\r\n", io.ErrUnexpectedEOF, 10},
+		{"missing trailer end", "0\r\nDigest: abc\r\n", io.ErrUnexpectedEOF, 16},
+		{"folded trailer", "0\r\nDigest: abc\r\n def\r\n\r\n", gsr7.ErrMalformedHeader, 16},
+		{"bare LF after trailer", "0\r\nDigest: abc\n\r\n", gsr7.ErrMalformedHeader, 14},
+		{"bare LF after trailer section", "0\r\nDigest: abc\r\n\n", gsr7.ErrMalformedHeader, 16},
+		{"body too large", "a\r\n0123456789\r\n1\r\nx\r\n0\r\n\r\n", gsr7.ErrBodyTooLarge, 15},
+		{"extensions too large", "1;abcde\r\nx\r\n1;abcde\r\nx\r\n0\r\n\r\n", gsr7.ErrBodyTooLarge, 12},
+	}
+	for _, tc := range testData {
+		t.Run(
+			tc.name, func(t *testing.T) {
+				stream := gsr7.NewChunkedReadableStream(strings.NewReader(tc.input), gsr7.ParseLimits{MaxBodySize: 10})
+				_, err := io.ReadAll(stream)
+				var parseError *gsr7.ParseError
+				if !errors.As(err, &parseError) {
+					t.Fatalf("expected a parse error, got %v", err)
+				}
+				assertEquals(t, errors.Is(err, tc.kind), true, "incorrect error: %v", err)
+				assertEquals(t, parseError.Offset, tc.offset, "incorrect offset")
+				_, err = stream.Read(make([]byte, 1))
+				assertEquals(t, errors.Is(err, tc.kind), true, "the error is not retained: %v", err)
+			},
+		)
+	}
+}
+
+func TestChunkedReadableStreamSeek(t *testing.T) {
+	input := "3\r\nabc\r\n3\r\ndef\r\n0\r\n\r\n"
+	stream := gsr7.NewChunkedReadableStream(strings.NewReader(input), gsr7.ParseLimits{})
+	buffer := make([]byte, 2)
+	_, _ = io.ReadFull(stream, buffer)
+	assertEquals(t, string(buffer), "ab", "incorrect first read")
+	assertEquals(t, stream.GetTrailers().Len(), 0, "trailers available before EOF")
+
+	position, err := stream.Seek(-1, io.SeekEnd)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertEquals(t, position, int64(5), "incorrect position")
+	_, _ = io.ReadFull(stream, buffer[:1])
+	assertEquals(t, string(buffer[:1]), "f", "incorrect read after seek")
+
+	_, _ = stream.Seek(0, io.SeekStart)
+	rest, _ := io.ReadAll(stream)
+	assertEquals(t, string(rest), "abcdef", "incorrect read from start")
+	assertEquals(t, stream.String(), "abcdef", "incorrect content")
+}
+
+func TestChunkedReadableStreamLeavesReader(t *testing.T) {
+	r := bufio.NewReader(strings.NewReader("3\r\nabc\r\n0\r\nX-A: 1\r\n\r\nnext"))
+	stream := gsr7.NewChunkedReadableStream(r, gsr7.ParseLimits{})
+	assertEquals(t, stream.String(), "abc", "incorrect content")
+	assertEquals(t, stream.GetTrailers().GetHeaderLine("X-A"), "1", "incorrect trailer")
+	rest, _ := io.ReadAll(r)
+	assertEquals(t, string(rest), "next", "incorrect remaining input")
+}
+
+func TestChunkedWritableStream(t *testing.T) {
+	var output bytes.Buffer
+	stream := gsr7.NewChunkedWritableStream(&output)
+	n, err := stream.Write(nil)
+	assertEquals(t, n, 0, "incorrect length of empty write")
+	assertEquals(t, err == nil, true, "empty write failed")
+	assertEquals(t, stream.WriteChunk(nil) != nil, true, "empty chunk accepted")
+	assertEquals(
+		t, stream.WriteChunk([]byte("a"), gsr7.ChunkExtension{Name: "a b"}) != nil, true, "invalid extension accepted",
+	)
+	assertEquals(
+		t, stream.WriteChunk([]byte("a"), gsr7.ChunkExtension{Name: "a", Value: "\r\n"}) != nil, true,
+		"invalid extension value accepted",
+	)
+	_ = stream.WriteChunk([]byte("a"), gsr7.ChunkExtension{Name: "x"}, gsr7.ChunkExtension{Name: "y", Value: `"\`})
+	assertEquals(
+		t, stream.CloseWithTrailers(gsr7.NewHeaders().WithHeader("Content-Length", "1")) != nil, true,
+		"forbidden trailer accepted",
+	)
+	if err := stream.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertEquals(t, stream.Close() != nil, true, "second close succeeded")
+	_, err = stream.Write([]byte("a"))
+	assertEquals(t, err != nil, true, "write after close succeeded")
+	assertEquals(t, output.String(), "1;x;y=\"\\\"\\\\\"\r\na\r\n0\r\n\r\n", "incorrect output")
+
+	decoded := gsr7.NewChunkedReadableStream(&output, gsr7.ParseLimits{})
+	assertEquals(t, decoded.String(), "a", "incorrect round trip")
+	assertEquals(t, fmt.Sprint(decoded.GetChunkExtensions()), `[{x } {y "\}]`, "incorrect extensions")
+}
+
+//endregion
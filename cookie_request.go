@@ -3,6 +3,7 @@ package gsr7
 import (
 	"fmt"
 	"net/url"
+	"strings"
 )
 
 // RequestCookie is a cookie that is set in a request using the Cookie header. It only contains the name and value
@@ -83,4 +84,46 @@ func (r requestCookie) Encode() string {
 	return fmt.Sprintf("%s=%s", r.name, url.QueryEscape(r.value))
 }
 
+// parseRequestCookies parses the values of the Cookie header. Multiple Cookie headers are treated as one, which is
+// how HTTP/2 transmits them. Cookies with invalid names are skipped.
+//
+// See https://datatracker.ietf.org/doc/html/rfc6265#section-5.4 for details.
+func parseRequestCookies(headerValues []string) []RequestCookie {
+	var cookies []RequestCookie
+	for _, headerValue := range headerValues {
+		for _, pair := range strings.Split(headerValue, ";") {
+			pair = strings.TrimSpace(pair)
+			if pair == "" {
+				continue
+			}
+			name, value := pair, ""
+			if i := strings.IndexByte(pair, '='); i >= 0 {
+				name, value = pair[:i], pair[i+1:]
+			}
+			if decoded, err := url.QueryUnescape(value); err == nil {
+				value = decoded
+			}
+			cookie, err := NewRequestCookie(name)
+			if err != nil {
+				continue
+			}
+			cookies = append(cookies, cookie.WithValue(value))
+		}
+	}
+	return cookies
+}
+
+// withRequestCookies replaces the Cookie header with the specified cookies. If there are no cookies the header is
+// removed.
+func withRequestCookies(headers Headers, cookies []RequestCookie) Headers {
+	if len(cookies) == 0 {
+		return headers.WithoutHeader("Cookie")
+	}
+	encoded := make([]string, len(cookies))
+	for i, cookie := range cookies {
+		encoded[i] = cookie.Encode()
+	}
+	return headers.WithHeader("Cookie", strings.Join(encoded, "; "))
+}
+
 //endregion
@@ -3,8 +3,11 @@ package gsr7
 import (
 	"fmt"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
+
+	"go.debugged.it/gsr7/publicsuffix"
 )
 
 // ResponseCookie is a cookie with additional information on cookie handling. This is sent to the client using the
@@ -18,15 +21,27 @@ type ResponseCookie interface {
 	// indicating all subdomains. See https://datatracker.ietf.org/doc/html/rfc6265#section-5.2.3 for details.
 	GetDomain() string
 	// WithDomain returns a new cookie object valid for the specified domain. This function does not modify the
-	// original cookie. The function panics if the domain is not valid for cookies.
+	// original cookie. The function panics if the domain is not valid for cookies. Public suffixes listed in the
+	// Public Suffix List, such as "com" or "co.uk", are not valid since browsers reject such supercookies. Unlisted
+	// single-label domains, such as "localhost", are accepted. A site that is itself a public suffix should not set a
+	// domain, which results in a host-only cookie.
 	//
 	// See https://datatracker.ietf.org/doc/html/rfc6265#section-5.2.3 for details.
 	WithDomain(domain string) ResponseCookie
 	// WithDomainE returns a new cookie object valid for the specified domain. This function does not modify the
-	// original cookie. The function returns an error if the domain is not valid for cookies.
+	// original cookie. The function returns an error if the domain is not valid for cookies. Public suffixes listed in
+	// the Public Suffix List, such as "com" or "co.uk", are not valid since browsers reject such supercookies.
+	// Unlisted single-label domains, such as "localhost", are accepted. A site that is itself a public suffix should
+	// not set a domain, which results in a host-only cookie.
 	//
 	// See https://datatracker.ietf.org/doc/html/rfc6265#section-5.2.3 for details.
 	WithDomainE(domain string) (ResponseCookie, error)
+	// ValidateOrigin checks if a client may store the cookie it received in response to a request to requestURI, as
+	// a cookie jar would. The request host must domain-match the domain of the cookie, and the domain must not be a
+	// public suffix unless it is identical to the request host. Cookies without a domain are always accepted.
+	//
+	// See https://datatracker.ietf.org/doc/html/rfc6265#section-5.3 for details.
+	ValidateOrigin(requestURI URI) error
 
 	// GetPath returns the path the cookie is valid for.
 	// See https://datatracker.ietf.org/doc/html/rfc6265#section-5.2.4 for details.
@@ -229,6 +244,25 @@ func (r responseCookie) WithDomainE(domain string) (ResponseCookie, error) {
 	}, nil
 }
 
+func (r responseCookie) ValidateOrigin(requestURI URI) error {
+	domain := strings.ToLower(strings.TrimPrefix(r.domain, "."))
+	if domain == "" {
+		return nil
+	}
+	host := strings.ToLower(requestURI.GetHost())
+	if host == domain {
+		return nil
+	}
+	if publicsuffix.IsPublicSuffix(domain) {
+		return fmt.Errorf("the cookie domain %s is a public suffix", r.domain)
+	}
+	kind := requestURI.GetHostKind()
+	if kind != HostKindRegName || !strings.HasSuffix(host, "."+domain) {
+		return fmt.Errorf("the cookie domain %s does not match the request host %s", r.domain, requestURI.GetHost())
+	}
+	return nil
+}
+
 func (r responseCookie) GetPath() string {
 	return r.path
 }
@@ -374,4 +408,87 @@ func (r responseCookie) Encode() string {
 	return strings.Join(parts, "; ")
 }
 
+// cookieDateLayouts are the date formats accepted in the Expires attribute of received cookies. Besides the preferred
+// format of RFC 1123, the obsolete formats of RFC 850 and asctime are still in use.
+var cookieDateLayouts = []string{
+	time.RFC1123,
+	"Mon, 02-Jan-2006 15:04:05 MST",
+	"Monday, 02-Jan-06 15:04:05 MST",
+	time.ANSIC,
+}
+
+// parseResponseCookies parses the values of the Set-Cookie header, one cookie per value. Cookies with invalid names
+// are skipped, as are attributes with invalid values.
+//
+// See https://datatracker.ietf.org/doc/html/rfc6265#section-5.2 for details.
+func parseResponseCookies(headerValues []string) []ResponseCookie {
+	var cookies []ResponseCookie
+	for _, headerValue := range headerValues {
+		parts := strings.Split(headerValue, ";")
+		pair := strings.TrimSpace(parts[0])
+		i := strings.IndexByte(pair, '=')
+		if i < 0 {
+			continue
+		}
+		name, value := strings.TrimSpace(pair[:i]), strings.TrimSpace(pair[i+1:])
+		if name == "" || validate(validateCookieName(name)) != nil {
+			continue
+		}
+		if decoded, err := url.QueryUnescape(value); err == nil {
+			value = decoded
+		}
+		cookie := &responseCookie{
+			name:  name,
+			value: value,
+		}
+		for _, attribute := range parts[1:] {
+			attribute = strings.TrimSpace(attribute)
+			attributeName, attributeValue := attribute, ""
+			if i := strings.IndexByte(attribute, '='); i >= 0 {
+				attributeName = strings.TrimSpace(attribute[:i])
+				attributeValue = strings.TrimSpace(attribute[i+1:])
+			}
+			switch strings.ToLower(attributeName) {
+			case "":
+			case "path":
+				cookie.path = attributeValue
+			case "domain":
+				cookie.domain = attributeValue
+			case "expires":
+				for _, layout := range cookieDateLayouts {
+					if expires, err := time.Parse(layout, attributeValue); err == nil {
+						cookie.expires = &expires
+						break
+					}
+				}
+			case "max-age":
+				if maxAge, err := strconv.Atoi(attributeValue); err == nil {
+					cookie.maxAge = &maxAge
+				}
+			case "secure":
+				cookie.secure = true
+			case "httponly":
+				cookie.httpOnly = true
+			default:
+				cookie.extensions = append(cookie.extensions, attribute)
+			}
+		}
+		cookies = append(cookies, cookie)
+	}
+	return cookies
+}
+
+// withResponseCookies replaces the Set-Cookie header with the specified cookies, one value per cookie. If there are
+// no cookies the header is removed.
+func withResponseCookies(headers Headers, cookies []ResponseCookie) Headers {
+	if len(cookies) == 0 {
+		return headers.WithoutHeader("Set-Cookie")
+	}
+	encoded := make([]string, len(cookies))
+	for i, cookie := range cookies {
+		encoded[i] = cookie.Encode()
+	}
+	return headers.WithHeaderValues("Set-Cookie", encoded)
+}
+
 //endregion
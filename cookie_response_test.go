@@ -2,6 +2,7 @@ package gsr7_test
 
 import (
 	"fmt"
+	"testing"
 	"time"
 
 	"go.debugged.it/gsr7"
@@ -17,3 +18,60 @@ func ExampleResponseCookie() {
 	fmt.Printf("Set-Cookie: %s", cookie.Encode())
 	// Output: Set-Cookie: foo=bar; path=/; domain=example.com; expires=Thu, 01 Jan 1970 00:00:00 UTC
 }
+
+func TestResponseCookieWithDomainPublicSuffix(t *testing.T) {
+	for _, domain := range []string{"com", ".com", "co.uk", ".CO.UK", "github.io"} {
+		t.Run(
+			domain, func(t *testing.T) {
+				if _, err := gsr7.NewResponseCookie("foo").WithDomainE(domain); err == nil {
+					t.Fatalf("setting the domain %s did not result in an error", domain)
+				}
+			},
+		)
+	}
+	for _, domain := range []string{
+		"example.com", ".example.co.uk", "octocat.github.io", "192.0.2.1", "", "localhost", ".intranet",
+	} {
+		t.Run(
+			domain, func(t *testing.T) {
+				if _, err := gsr7.NewResponseCookie("foo").WithDomainE(domain); err != nil {
+					t.Fatalf("failed to set the domain %s (%v)", domain, err)
+				}
+			},
+		)
+	}
+}
+
+func TestResponseCookieValidateOrigin(t *testing.T) {
+	testData := []struct {
+		domain     string
+		requestURI string
+		valid      bool
+	}{
+		{"", "https://www.example.com/", true},
+		{"example.com", "https://www.example.com/", true},
+		{".example.com", "https://www.example.com/", true},
+		{"example.com", "https://example.com/", true},
+		{"www.example.com", "https://example.com/", false},
+		{"example.com", "https://www.example.org/", false},
+		{"example.com", "https://notexample.com/", false},
+		{"2.1", "https://192.0.2.1/", false},
+	}
+	for _, testCase := range testData {
+		t.Run(
+			testCase.domain+" "+testCase.requestURI, func(t *testing.T) {
+				cookie := gsr7.NewResponseCookie("foo")
+				if testCase.domain != "" {
+					cookie = cookie.WithDomain(testCase.domain)
+				}
+				err := cookie.ValidateOrigin(gsr7.ParseURI(testCase.requestURI))
+				if testCase.valid && err != nil {
+					t.Fatalf("the cookie was rejected (%v)", err)
+				}
+				if !testCase.valid && err == nil {
+					t.Fatalf("the cookie was not rejected")
+				}
+			},
+		)
+	}
+}
@@ -1,3 +1,7 @@
 module go.debugged.it/gsr7
 
-go 1.18
+go 1.19
+
+require golang.org/x/net v0.35.0
+
+require golang.org/x/text v0.22.0 // indirect
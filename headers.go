@@ -0,0 +1,237 @@
+package gsr7
+
+import (
+	"strings"
+	"sync"
+)
+
+//region Interface
+
+// Headers is an immutable, ordered collection of HTTP header fields. Names are matched case-insensitively, but the
+// casing of the name is kept for output. The zero value is an empty collection.
+//
+// Copies share their structure: a With* call appends the change to a log shared with the original instead of copying
+// every field, so modifying a message with many headers is cheap. Headers values are safe for concurrent use.
+//
+// See https://datatracker.ietf.org/doc/html/rfc9110#section-5 for details.
+type Headers struct {
+	// fields is a log of field states. The last entry for a name is its current state. Entries are never modified
+	// once written, so copies can share the backing array.
+	fields []*headerField
+	// log coordinates appending to the shared backing array of fields.
+	log *headerLog
+}
+
+// NewHeaders creates an empty header collection. This is equivalent to the zero value.
+func NewHeaders() Headers {
+	return Headers{}
+}
+
+// Len returns the number of distinct header names.
+func (h Headers) Len() int {
+	return len(h.current())
+}
+
+// GetNames returns the header names in the order they were first added, using the casing of the most recent
+// WithHeader call for each name.
+func (h Headers) GetNames() []string {
+	current := h.current()
+	names := make([]string, len(current))
+	for i, field := range current {
+		names[i] = field.name
+	}
+	return names
+}
+
+// GetHeaders returns all headers in order. Each entry starts with the header name, followed by its values.
+func (h Headers) GetHeaders() [][]string {
+	current := h.current()
+	result := make([][]string, len(current))
+	for i, field := range current {
+		entry := make([]string, 0, len(field.values)+1)
+		entry = append(entry, field.name)
+		result[i] = append(entry, field.values...)
+	}
+	return result
+}
+
+// HasHeader returns true if a header with the specified name is present. The name is matched case-insensitively.
+func (h Headers) HasHeader(name string) bool {
+	return h.find(headerKey(name)) != nil
+}
+
+// GetHeader returns a copy of the values of the specified header, or nil if the header is not present. The name is
+// matched case-insensitively.
+func (h Headers) GetHeader(name string) []string {
+	field := h.find(headerKey(name))
+	if field == nil {
+		return nil
+	}
+	values := make([]string, len(field.values))
+	copy(values, field.values)
+	return values
+}
+
+// GetHeaderLine returns the values of the specified header joined by a comma and a space, or an empty string if the
+// header is not present.
+func (h Headers) GetHeaderLine(name string) string {
+	field := h.find(headerKey(name))
+	if field == nil {
+		return ""
+	}
+	return strings.Join(field.values, ", ")
+}
+
+// WithHeader returns a copy with the specified header replaced by a single value. The header keeps its position if it
+// already exists, but takes the casing of the passed name. If the name or value is invalid a panic is thrown.
+func (h Headers) WithHeader(name, value string) Headers {
+	return Must(h.WithHeaderE(name, value))
+}
+
+// WithHeaderE returns a copy with the specified header replaced by a single value. The header keeps its position if
+// it already exists, but takes the casing of the passed name. If the name is not a valid token, or the value contains
+// CR, LF or NUL characters an error is returned.
+func (h Headers) WithHeaderE(name, value string) (Headers, error) {
+	return h.WithHeaderValuesE(name, []string{value})
+}
+
+// WithHeaderValues returns a copy with the specified header replaced by the specified values. If the name or one of
+// the values is invalid a panic is thrown.
+func (h Headers) WithHeaderValues(name string, values []string) Headers {
+	return Must(h.WithHeaderValuesE(name, values))
+}
+
+// WithHeaderValuesE returns a copy with the specified header replaced by the specified values. If the name or one of
+// the values is invalid an error is returned.
+func (h Headers) WithHeaderValuesE(name string, values []string) (Headers, error) {
+	if err := validate(validateHeaderName(name), validateHeaderValues(values)); err != nil {
+		return Headers{}, err
+	}
+	newValues := make([]string, len(values))
+	copy(newValues, values)
+	return h.append(&headerField{name: name, key: headerKey(name), values: newValues}), nil
+}
+
+// WithAddedHeader returns a copy with the value appended to the specified header, or with the header added if it
+// does not exist yet. An existing header keeps the casing of its name. If the name or value is invalid a panic is
+// thrown.
+func (h Headers) WithAddedHeader(name, value string) Headers {
+	return Must(h.WithAddedHeaderE(name, value))
+}
+
+// WithAddedHeaderE returns a copy with the value appended to the specified header, or with the header added if it
+// does not exist yet. An existing header keeps the casing of its name. If the name or value is invalid an error is
+// returned.
+func (h Headers) WithAddedHeaderE(name, value string) (Headers, error) {
+	if err := validate(validateHeaderName(name), validateHeaderValues([]string{value})); err != nil {
+		return Headers{}, err
+	}
+	key := headerKey(name)
+	field := &headerField{name: name, key: key, values: []string{value}}
+	if existing := h.find(key); existing != nil {
+		field.name = existing.name
+		field.values = make([]string, len(existing.values), len(existing.values)+1)
+		copy(field.values, existing.values)
+		field.values = append(field.values, value)
+	}
+	return h.append(field), nil
+}
+
+// WithoutHeader returns a copy with the specified header removed. The name is matched case-insensitively.
+func (h Headers) WithoutHeader(name string) Headers {
+	key := headerKey(name)
+	if h.find(key) == nil {
+		return h
+	}
+	return h.append(&headerField{key: key, removed: true})
+}
+
+//endregion
+
+//region Implementation
+
+type headerField struct {
+	// name is the name with its original casing.
+	name string
+	// key is the lowercase name used for lookups.
+	key    string
+	values []string
+	// removed marks the removal of the header.
+	removed bool
+}
+
+type headerLog struct {
+	lock sync.Mutex
+	// claimed is the number of entries in the shared backing array that belong to a Headers value. Only the value
+	// holding exactly this many entries may append in place.
+	claimed int
+}
+
+func headerKey(name string) string {
+	return strings.ToLower(name)
+}
+
+// find returns the current state of the header, or nil if it is not present.
+func (h Headers) find(key string) *headerField {
+	for i := len(h.fields) - 1; i >= 0; i-- {
+		if field := h.fields[i]; field.key == key {
+			if field.removed {
+				return nil
+			}
+			return field
+		}
+	}
+	return nil
+}
+
+// current returns the current state of all headers. Each header is placed at the position of the first entry after
+// its most recent removal, so replacing a header keeps its position.
+func (h Headers) current() []*headerField {
+	positions := map[string]int{}
+	var result []*headerField
+	for _, field := range h.fields {
+		position, ok := positions[field.key]
+		switch {
+		case field.removed:
+			if ok {
+				result[position] = nil
+				delete(positions, field.key)
+			}
+		case ok:
+			result[position] = field
+		default:
+			positions[field.key] = len(result)
+			result = append(result, field)
+		}
+	}
+	live := result[:0]
+	for _, field := range result {
+		if field != nil {
+			live = append(live, field)
+		}
+	}
+	return live
+}
+
+// append returns a copy with the field appended to the log. The backing array is shared if no other copy has
+// appended to it yet. If the log consists mostly of outdated entries, it is compacted instead.
+func (h Headers) append(field *headerField) Headers {
+	if len(h.fields) >= 16 && len(h.fields) > 2*h.Len() {
+		fields := append(h.current(), field)
+		return Headers{fields: fields, log: &headerLog{claimed: len(fields)}}
+	}
+	if h.log != nil {
+		h.log.lock.Lock()
+		defer h.log.lock.Unlock()
+		if h.log.claimed == len(h.fields) && len(h.fields) < cap(h.fields) {
+			h.log.claimed++
+			return Headers{fields: append(h.fields, field), log: h.log}
+		}
+	}
+	fields := make([]*headerField, len(h.fields), 2*len(h.fields)+4)
+	copy(fields, h.fields)
+	fields = append(fields, field)
+	return Headers{fields: fields, log: &headerLog{claimed: len(fields)}}
+}
+
+//endregion
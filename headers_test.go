@@ -0,0 +1,144 @@
+package gsr7_test
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+
+	"go.debugged.it/gsr7"
+)
+
+//region Examples
+
+func ExampleHeaders() {
+	headers := gsr7.NewHeaders().
+		WithHeader("Content-Type", "text/html").
+		WithAddedHeader("Cache-Control", "no-cache").
+		WithAddedHeader("cache-control", "no-store")
+	fmt.Println(headers.GetHeaderLine("CACHE-CONTROL"))
+	fmt.Println(headers.GetHeaders())
+	// Output: no-cache, no-store
+	// [[Content-Type text/html] [Cache-Control no-cache no-store]]
+}
+
+//endregion
+
+//region Tests
+
+func TestHeadersOrderAndCasing(t *testing.T) {
+	headers := gsr7.NewHeaders().
+		WithHeader("X-First", "1").
+		WithHeader("Content-Type", "text/plain").
+		WithHeader("X-Last", "3").
+		WithHeader("content-type", "text/html")
+
+	expected := [][]string{{"X-First", "1"}, {"content-type", "text/html"}, {"X-Last", "3"}}
+	if !reflect.DeepEqual(headers.GetHeaders(), expected) {
+		t.Fatalf("incorrect headers: %v", headers.GetHeaders())
+	}
+	assertEquals(t, headers.Len(), 3, "incorrect length: %d", headers.Len())
+	assertEquals(t, headers.HasHeader("CONTENT-TYPE"), true, "case-insensitive lookup failed")
+	assertEquals(t, headers.GetHeaderLine("Content-Type"), "text/html", "incorrect header line")
+
+	headers = headers.WithAddedHeader("X-FIRST", "2")
+	if !reflect.DeepEqual(headers.GetNames(), []string{"X-First", "content-type", "X-Last"}) {
+		t.Fatalf("adding a value changed the name: %v", headers.GetNames())
+	}
+	if !reflect.DeepEqual(headers.GetHeader("x-first"), []string{"1", "2"}) {
+		t.Fatalf("incorrect values: %v", headers.GetHeader("x-first"))
+	}
+
+	headers = headers.WithoutHeader("x-first").WithHeader("X-First", "4")
+	if !reflect.DeepEqual(headers.GetNames(), []string{"content-type", "X-Last", "X-First"}) {
+		t.Fatalf("a removed header kept its position: %v", headers.GetNames())
+	}
+}
+
+func TestHeadersMissing(t *testing.T) {
+	var headers gsr7.Headers
+	assertEquals(t, headers.HasHeader("X-Missing"), false, "missing header reported as present")
+	assertEquals(t, headers.GetHeaderLine("X-Missing"), "", "incorrect header line")
+	if headers.GetHeader("X-Missing") != nil {
+		t.Fatalf("missing header returned values")
+	}
+	assertEquals(t, headers.WithoutHeader("X-Missing").Len(), 0, "removing a missing header added a header")
+}
+
+func TestHeadersValidation(t *testing.T) {
+	for _, name := range []string{"", "X Header", "X-Header:", "X-Ü", "X\r\nInjected"} {
+		if _, err := gsr7.NewHeaders().WithHeaderE(name, "value"); err == nil {
+			t.Fatalf("the invalid header name %q did not result in an error", name)
+		}
+		if _, err := gsr7.NewHeaders().WithAddedHeaderE(name, "value"); err == nil {
+			t.Fatalf("the invalid header name %q did not result in an error", name)
+		}
+	}
+	for _, value := range []string{"a\rb", "a\nb", "a\x00b"} {
+		if _, err := gsr7.NewHeaders().WithHeaderE("X-Header", value); err == nil {
+			t.Fatalf("the invalid header value %q did not result in an error", value)
+		}
+		if _, err := gsr7.NewHeaders().WithHeaderValuesE("X-Header", []string{"valid", value}); err == nil {
+			t.Fatalf("the invalid header value %q did not result in an error", value)
+		}
+	}
+	if _, err := gsr7.NewHeaders().WithHeaderE("!#$%&'*+-.^_`|~0aZ", "tab\tand ünicode"); err != nil {
+		t.Fatalf("a valid header was rejected (%v)", err)
+	}
+}
+
+func TestHeadersImmutability(t *testing.T) {
+	base := gsr7.NewHeaders().WithHeader("X-Base", "1")
+	a := base.WithHeader("X-A", "a")
+	b := base.WithHeader("X-B", "b")
+	c := a.WithAddedHeader("X-Base", "2")
+
+	assertEquals(t, base.Len(), 1, "the original was modified")
+	assertEquals(t, a.HasHeader("X-B"), false, "copies affected each other")
+	assertEquals(t, b.HasHeader("X-A"), false, "copies affected each other")
+	assertEquals(t, a.GetHeaderLine("X-Base"), "1", "copies affected each other")
+	assertEquals(t, c.GetHeaderLine("X-Base"), "1, 2", "incorrect values")
+
+	values := []string{"x", "y"}
+	d := base.WithHeaderValues("X-Values", values)
+	values[0] = "changed"
+	d.GetHeader("X-Values")[1] = "changed"
+	if !reflect.DeepEqual(d.GetHeader("X-Values"), []string{"x", "y"}) {
+		t.Fatalf("the values were modified from outside: %v", d.GetHeader("X-Values"))
+	}
+}
+
+func TestHeadersManyModifications(t *testing.T) {
+	headers := gsr7.NewHeaders().WithHeader("X-Kept", "kept")
+	for i := 0; i < 1000; i++ {
+		headers = headers.WithHeader("X-Counter", strconv.Itoa(i))
+		if i%10 == 0 {
+			headers = headers.WithoutHeader("X-Counter")
+		}
+	}
+	expected := [][]string{{"X-Kept", "kept"}, {"X-Counter", "999"}}
+	if !reflect.DeepEqual(headers.GetHeaders(), expected) {
+		t.Fatalf("incorrect headers: %v", headers.GetHeaders())
+	}
+}
+
+func TestHeadersConcurrentCopies(t *testing.T) {
+	base := gsr7.NewHeaders().WithHeader("X-Base", "1")
+	results := make([]gsr7.Headers, 50)
+	wg := sync.WaitGroup{}
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = base.WithHeader("X-Index", strconv.Itoa(i))
+		}(i)
+	}
+	wg.Wait()
+	for i, headers := range results {
+		assertEquals(t, headers.GetHeaderLine("X-Index"), strconv.Itoa(i), "copies affected each other")
+		assertEquals(t, headers.Len(), 2, "incorrect length")
+	}
+}
+
+//endregion
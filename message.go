@@ -1,9 +1,18 @@
 package gsr7
 
+import (
+	"strings"
+)
+
+//region Interface
+
+// Message contains the methods shared by requests and responses. Header names are matched case-insensitively. See
+// Headers for the validation rules of header names and values.
 type Message[MessageType any, BodyType any, CookieType any] interface {
 	GetProtocolVersion() Version
 	WithProtocolVersion(version Version) MessageType
 
+	// GetHeaders returns all headers in order. Each entry starts with the header name, followed by its values.
 	GetHeaders() [][]string
 
 	HasHeader(name string) bool
@@ -12,17 +21,108 @@ type Message[MessageType any, BodyType any, CookieType any] interface {
 
 	WithHeader(name, value string) MessageType
 	WithHeaderE(name, value string) (MessageType, error)
-	WithHeaderValues(name, value []string) MessageType
-	WithHeaderValuesE(name, value []string) (MessageType, error)
+	WithHeaderValues(name string, value []string) MessageType
+	WithHeaderValuesE(name string, value []string) (MessageType, error)
 	WithAddedHeader(name, value string) MessageType
 	WithAddedHeaderE(name, value string) (MessageType, error)
 	WithoutHeader(name string) MessageType
 
 	GetBody() BodyType
-	WithBody(BodyType) ClientRequest
-	WithBodyE(BodyType) (ClientRequest, error)
+	WithBody(BodyType) MessageType
+	WithBodyE(BodyType) (MessageType, error)
+
+	// GetTrailers returns the fields sent after the body. Trailers received with the body, for example from a
+	// ChunkedReadableStream, are only available once the body has been read to the end. Fields that are not allowed
+	// in trailers are omitted.
+	GetTrailers() Headers
+	// WithTrailer returns a copy of the message with the specified trailer field replaced by a single value. If the
+	// name or value is invalid, or the field is not allowed in trailers a panic is thrown.
+	WithTrailer(name, value string) MessageType
+	// WithTrailerE returns a copy of the message with the specified trailer field replaced by a single value. Fields
+	// needed before the body can be processed, such as Content-Length, Host or Authorization, are not allowed in
+	// trailers and result in an error, as do invalid names and values.
+	WithTrailerE(name, value string) (MessageType, error)
+	// WithDeclaredTrailers returns a copy of the message with the Trailer header announcing the specified trailer
+	// fields. If no names are passed, the Trailer header is removed. If a name is not allowed in trailers a panic is
+	// thrown.
+	WithDeclaredTrailers(names ...string) MessageType
+	// WithDeclaredTrailersE returns a copy of the message with the Trailer header announcing the specified trailer
+	// fields. If no names are passed, the Trailer header is removed. If a name is not allowed in trailers an error is
+	// returned.
+	WithDeclaredTrailersE(names ...string) (MessageType, error)
 
 	GetCookies() []CookieType
 	WithCookie(cookie CookieType) MessageType
 	WithCookies(cookies []CookieType) MessageType
 }
+
+//endregion
+
+//region Implementation
+
+// message holds the fields shared by all message implementations. The With* methods are implemented by the message
+// types themselves since they return the concrete type.
+type message struct {
+	protocolVersion Version
+	headers         Headers
+	// trailers holds the trailers set with WithTrailer. Trailers received with the body are provided by the body.
+	trailers Headers
+}
+
+func (m message) GetProtocolVersion() Version {
+	return m.protocolVersion
+}
+
+func (m message) GetHeaders() [][]string {
+	return m.headers.GetHeaders()
+}
+
+func (m message) HasHeader(name string) bool {
+	return m.headers.HasHeader(name)
+}
+
+func (m message) GetHeader(name string) []string {
+	return m.headers.GetHeader(name)
+}
+
+func (m message) GetHeaderLine(name string) string {
+	return m.headers.GetHeaderLine(name)
+}
+
+// trailersOf merges the trailers provided by the body, if any, with the trailers set on the message. The latter take
+// precedence.
+func (m message) trailersOf(body any) Headers {
+	trailers := NewHeaders()
+	if source, ok := body.(interface{ GetTrailers() Headers }); ok {
+		for _, field := range source.GetTrailers().GetHeaders() {
+			if validate(validateTrailerName(field[0])) == nil {
+				trailers = trailers.WithHeaderValues(field[0], field[1:])
+			}
+		}
+	}
+	for _, field := range m.trailers.GetHeaders() {
+		trailers = trailers.WithHeaderValues(field[0], field[1:])
+	}
+	return trailers
+}
+
+func (m message) withTrailerE(name, value string) (Headers, error) {
+	if err := validate(validateTrailerName(name)); err != nil {
+		return Headers{}, err
+	}
+	return m.trailers.WithHeaderE(name, value)
+}
+
+func (m message) withDeclaredTrailersE(names []string) (Headers, error) {
+	if len(names) == 0 {
+		return m.headers.WithoutHeader("Trailer"), nil
+	}
+	for _, name := range names {
+		if err := validate(validateTrailerName(name)); err != nil {
+			return Headers{}, err
+		}
+	}
+	return m.headers.WithHeaderE("Trailer", strings.Join(names, ", "))
+}
+
+//endregion
@@ -1,16 +1,47 @@
 package gsr7
 
+//region Interface
+
+// Request contains the methods shared by client and server requests.
 type Request[RequestType any, BodyType any] interface {
 	Message[RequestType, BodyType, RequestCookie]
 
+	// GetRequestTarget returns the request target as it appears in the request line. Unless it was set with
+	// WithRequestTarget or WithRequestTargetString, it is derived from the method and the URI as described in
+	// NewRequestTargetE for a request sent directly to the origin server.
 	GetRequestTarget() string
+	// GetParsedRequestTarget returns the request target in its parsed form. See GetRequestTarget for details.
+	GetParsedRequestTarget() RequestTarget
+	// WithRequestTarget returns a copy of the request with the specified request target, for example in absolute-form
+	// for a request sent via a proxy. The URI is updated to the target URI resolved against the current URI, so it
+	// stays consistent with the request target, and the Host header is updated as in WithURI.
+	WithRequestTarget(requestTarget RequestTarget) RequestType
+	// WithRequestTargetString returns a copy of the request with the request target parsed from the string, for
+	// example "*" for a server-wide OPTIONS request. The URI and the Host header are updated as in WithRequestTarget.
+	// An error is returned if the request target is not valid for the method of the request, see
+	// ParseRequestTargetE for details.
 	WithRequestTargetString(requestTarget string) (RequestType, error)
 
+	// GetMethod returns the request method. Methods are case-sensitive.
 	GetMethod() string
+	// WithMethod returns a copy of the request with the specified method. If the method is not a valid token a panic
+	// is thrown.
 	WithMethod(method string) RequestType
+	// WithMethodE returns a copy of the request with the specified method. If the method is not a valid token an error
+	// is returned.
 	WithMethodE(method string) (RequestType, error)
 
+	// GetURI returns the URI of the request.
 	GetURI() URI
+	// WithURI returns a copy of the request with the specified URI. If the URI contains a host, the Host header is
+	// updated to the host and port of the URI. If the URI has no host, the Host header is left unchanged. A request
+	// target set with WithRequestTarget is kept, so only requests without one derive it from the new URI. If the URI
+	// is nil a panic is thrown.
 	WithURI(uri URI) RequestType
+	// WithURIPreserveHost returns a copy of the request with the specified URI, keeping the Host header if it is
+	// present and not empty. Otherwise, the Host header is set from the URI as in WithURI. If the URI is nil a panic
+	// is thrown.
 	WithURIPreserveHost(uri URI) RequestType
 }
+
+//endregion
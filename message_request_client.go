@@ -1,5 +1,250 @@
 package gsr7
 
+import (
+	"strconv"
+)
+
+//region Interface
+
+// ClientRequest is a request that is sent by a HTTP client. Its body is written by the client when sending the
+// request.
 type ClientRequest interface {
-    Request[ClientRequest, WritableStream]
+	Request[ClientRequest, WritableStream]
+}
+
+// NewClientRequest creates a HTTP/1.1 request with the specified method and URI. The Host header is set from the
+// authority of the URI. If the method is invalid or the URI is nil a panic is thrown.
+func NewClientRequest(method string, uri URI) ClientRequest {
+	return Must(NewClientRequestE(method, uri))
+}
+
+// NewClientRequestE creates a HTTP/1.1 request with the specified method and URI. The Host header is set from the
+// authority of the URI. If the method is not a valid token or the URI is nil an error is returned.
+func NewClientRequestE(method string, uri URI) (ClientRequest, error) {
+	if err := validate(validateMethod(method), validateRequestURI(uri)); err != nil {
+		return nil, err
+	}
+	r := &clientRequest{
+		message: message{
+			protocolVersion: HTTP11,
+		},
+		method: method,
+		uri:    uri,
+	}
+	r.headers = withHostFromURI(r.headers, uri, false)
+	return r, nil
+}
+
+//endregion
+
+//region Implementation
+
+type clientRequest struct {
+	message
+	method string
+	uri    URI
+	// requestTarget overrides the request target derived from the method and the URI if it is not nil.
+	requestTarget RequestTarget
+	body          WritableStream
+}
+
+func (r clientRequest) WithProtocolVersion(version Version) ClientRequest {
+	r.protocolVersion = version
+	return &r
+}
+
+func (r clientRequest) WithHeader(name, value string) ClientRequest {
+	return Must(r.WithHeaderE(name, value))
+}
+
+func (r clientRequest) WithHeaderE(name, value string) (ClientRequest, error) {
+	headers, err := r.headers.WithHeaderE(name, value)
+	if err != nil {
+		return nil, err
+	}
+	r.headers = headers
+	return &r, nil
+}
+
+func (r clientRequest) WithHeaderValues(name string, values []string) ClientRequest {
+	return Must(r.WithHeaderValuesE(name, values))
+}
+
+func (r clientRequest) WithHeaderValuesE(name string, values []string) (ClientRequest, error) {
+	headers, err := r.headers.WithHeaderValuesE(name, values)
+	if err != nil {
+		return nil, err
+	}
+	r.headers = headers
+	return &r, nil
+}
+
+func (r clientRequest) WithAddedHeader(name, value string) ClientRequest {
+	return Must(r.WithAddedHeaderE(name, value))
+}
+
+func (r clientRequest) WithAddedHeaderE(name, value string) (ClientRequest, error) {
+	headers, err := r.headers.WithAddedHeaderE(name, value)
+	if err != nil {
+		return nil, err
+	}
+	r.headers = headers
+	return &r, nil
+}
+
+func (r clientRequest) WithoutHeader(name string) ClientRequest {
+	r.headers = r.headers.WithoutHeader(name)
+	return &r
+}
+
+func (r clientRequest) GetBody() WritableStream {
+	return r.body
+}
+
+func (r clientRequest) WithBody(body WritableStream) ClientRequest {
+	return Must(r.WithBodyE(body))
+}
+
+func (r clientRequest) WithBodyE(body WritableStream) (ClientRequest, error) {
+	r.body = body
+	return &r, nil
+}
+
+func (r clientRequest) GetTrailers() Headers {
+	return r.trailersOf(r.body)
+}
+
+func (r clientRequest) WithTrailer(name, value string) ClientRequest {
+	return Must(r.WithTrailerE(name, value))
+}
+
+func (r clientRequest) WithTrailerE(name, value string) (ClientRequest, error) {
+	trailers, err := r.withTrailerE(name, value)
+	if err != nil {
+		return nil, err
+	}
+	r.trailers = trailers
+	return &r, nil
 }
+
+func (r clientRequest) WithDeclaredTrailers(names ...string) ClientRequest {
+	return Must(r.WithDeclaredTrailersE(names...))
+}
+
+func (r clientRequest) WithDeclaredTrailersE(names ...string) (ClientRequest, error) {
+	headers, err := r.withDeclaredTrailersE(names)
+	if err != nil {
+		return nil, err
+	}
+	r.headers = headers
+	return &r, nil
+}
+
+func (r clientRequest) GetCookies() []RequestCookie {
+	return parseRequestCookies(r.headers.GetHeader("Cookie"))
+}
+
+func (r clientRequest) WithCookie(cookie RequestCookie) ClientRequest {
+	return r.WithCookies(append(r.GetCookies(), cookie))
+}
+
+func (r clientRequest) WithCookies(cookies []RequestCookie) ClientRequest {
+	r.headers = withRequestCookies(r.headers, cookies)
+	return &r
+}
+
+func (r clientRequest) GetRequestTarget() string {
+	return r.GetParsedRequestTarget().String()
+}
+
+func (r clientRequest) GetParsedRequestTarget() RequestTarget {
+	if r.requestTarget != nil {
+		return r.requestTarget
+	}
+	return requestTargetFor(r.method, r.uri)
+}
+
+func (r clientRequest) WithRequestTarget(requestTarget RequestTarget) ClientRequest {
+	r.requestTarget = requestTarget
+	r.uri = requestTarget.ResolveURI(r.uri)
+	r.headers = withHostFromURI(r.headers, r.uri, false)
+	return &r
+}
+
+func (r clientRequest) WithRequestTargetString(requestTarget string) (ClientRequest, error) {
+	target, err := ParseRequestTargetE(r.method, requestTarget)
+	if err != nil {
+		return nil, err
+	}
+	return r.WithRequestTarget(target), nil
+}
+
+func (r clientRequest) GetMethod() string {
+	return r.method
+}
+
+func (r clientRequest) WithMethod(method string) ClientRequest {
+	return Must(r.WithMethodE(method))
+}
+
+func (r clientRequest) WithMethodE(method string) (ClientRequest, error) {
+	if err := validate(validateMethod(method)); err != nil {
+		return nil, err
+	}
+	r.method = method
+	return &r, nil
+}
+
+func (r clientRequest) GetURI() URI {
+	return r.uri
+}
+
+func (r clientRequest) WithURI(uri URI) ClientRequest {
+	return Must(r.withURIE(uri, false))
+}
+
+func (r clientRequest) WithURIPreserveHost(uri URI) ClientRequest {
+	return Must(r.withURIE(uri, true))
+}
+
+// withURIE implements WithURI and WithURIPreserveHost. An error is returned if the URI is nil.
+func (r clientRequest) withURIE(uri URI, preserveHost bool) (ClientRequest, error) {
+	if err := validate(validateRequestURI(uri)); err != nil {
+		return nil, err
+	}
+	r.uri = uri
+	r.headers = withHostFromURI(r.headers, uri, preserveHost)
+	return &r, nil
+}
+
+// hostFromURI returns the value of the Host header for the URI, which is the host and the port, if any.
+func hostFromURI(uri URI) string {
+	host := uri.GetHost()
+	if host == "" {
+		return ""
+	}
+	if port := uri.GetPort(); port != nil {
+		host += ":" + strconv.Itoa(int(*port))
+	}
+	return host
+}
+
+// withHostFromURI updates the Host header from the URI as described in PSR-7. If the URI has no host, the headers
+// are left unchanged. If preserveHost is set, an existing non-empty Host header is kept. A new Host header is placed
+// first, as RFC 9112 section 3.2 recommends.
+func withHostFromURI(headers Headers, uri URI, preserveHost bool) Headers {
+	host := hostFromURI(uri)
+	if host == "" || (preserveHost && headers.GetHeaderLine("Host") != "") {
+		return headers
+	}
+	if headers.HasHeader("Host") {
+		return headers.WithHeader("Host", host)
+	}
+	result := NewHeaders().WithHeader("Host", host)
+	for _, header := range headers.GetHeaders() {
+		result = result.WithHeaderValues(header[0], header[1:])
+	}
+	return result
+}
+
+//endregion
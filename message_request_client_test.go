@@ -0,0 +1,200 @@
+package gsr7_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"go.debugged.it/gsr7"
+)
+
+//region Examples
+
+func ExampleNewClientRequest() {
+	request := gsr7.NewClientRequest("GET", gsr7.ParseURI("https://example.com:8443/search?q=gsr7"))
+	fmt.Println(request.GetMethod(), request.GetRequestTarget(), request.GetProtocolVersion())
+	fmt.Println(request.GetHeaderLine("Host"))
+	// Output: GET /search?q=gsr7 HTTP/1.1
+	// example.com:8443
+}
+
+//endregion
+
+//region Tests
+
+func TestNewClientRequest(t *testing.T) {
+	request := gsr7.NewClientRequest("POST", gsr7.ParseURI("http://example.com"))
+	assertEquals(t, request.GetMethod(), "POST", "incorrect method")
+	assertEquals(t, request.GetRequestTarget(), "/", "incorrect request target")
+	assertEquals(t, request.GetHeaderLine("host"), "example.com", "incorrect Host header")
+	assertEquals(t, request.GetURI().String(), "http://example.com", "incorrect URI")
+
+	request = gsr7.NewClientRequest("GET", gsr7.ParseURI("/relative"))
+	assertEquals(t, request.HasHeader("Host"), false, "Host header set for a URI without host")
+
+	for _, method := range []string{"", "GET /", "GÉT", "GET\r\n"} {
+		if _, err := gsr7.NewClientRequestE(method, gsr7.ParseURI("http://example.com/")); err == nil {
+			t.Fatalf("the invalid method %q did not result in an error", method)
+		}
+	}
+	if _, err := gsr7.NewClientRequestE("GET", nil); err == nil {
+		t.Fatalf("a nil URI did not result in an error")
+	}
+}
+
+func TestClientRequestWithURI(t *testing.T) {
+	testData := []struct {
+		name         string
+		initialHost  string
+		uri          string
+		preserveHost bool
+		expectedHost string
+	}{
+		{"update", "old.example.com", "http://new.example.com/", false, "new.example.com"},
+		{"update with port", "old.example.com", "http://new.example.com:8080/", false, "new.example.com:8080"},
+		{"no host in URI", "old.example.com", "/path", false, "old.example.com"},
+		{"preserve", "old.example.com", "http://new.example.com/", true, "old.example.com"},
+		{"preserve empty", "", "http://new.example.com/", true, "new.example.com"},
+		{"preserve missing", "-", "http://new.example.com/", true, "new.example.com"},
+		{"preserve without host in URI", "-", "/path", true, ""},
+	}
+	for _, testCase := range testData {
+		t.Run(
+			testCase.name, func(t *testing.T) {
+				request := gsr7.NewClientRequest("GET", gsr7.ParseURI("/"))
+				if testCase.initialHost != "-" {
+					request = request.WithHeader("Host", testCase.initialHost)
+				}
+				uri := gsr7.ParseURI(testCase.uri)
+				if testCase.preserveHost {
+					request = request.WithURIPreserveHost(uri)
+				} else {
+					request = request.WithURI(uri)
+				}
+				assertEquals(t, request.GetURI().String(), uri.String(), "the URI was not updated")
+				assertEquals(
+					t, request.GetHeaderLine("Host"), testCase.expectedHost, "incorrect Host header: %s",
+					request.GetHeaderLine("Host"),
+				)
+			},
+		)
+	}
+}
+
+func TestClientRequestWithNilURI(t *testing.T) {
+	request := gsr7.NewClientRequest("GET", gsr7.ParseURI("http://example.com/"))
+	for name, setter := range map[string]func(gsr7.URI) gsr7.ClientRequest{
+		"WithURI":             request.WithURI,
+		"WithURIPreserveHost": request.WithURIPreserveHost,
+	} {
+		t.Run(
+			name, func(t *testing.T) {
+				defer func() {
+					if recover() == nil {
+						t.Fatalf("%s with a nil URI did not panic", name)
+					}
+				}()
+				setter(nil)
+			},
+		)
+	}
+}
+
+func TestClientRequestHostFirst(t *testing.T) {
+	request := gsr7.NewClientRequest("GET", gsr7.ParseURI("/")).
+		WithHeader("Accept", "*/*").
+		WithURI(gsr7.ParseURI("http://example.com/"))
+	expected := [][]string{{"Host", "example.com"}, {"Accept", "*/*"}}
+	if !reflect.DeepEqual(request.GetHeaders(), expected) {
+		t.Fatalf("incorrect headers: %v", request.GetHeaders())
+	}
+}
+
+func TestClientRequestRequestTarget(t *testing.T) {
+	request := gsr7.NewClientRequest("GET", gsr7.ParseURI("http://example.com/a/b?c=d#fragment"))
+	assertEquals(t, request.GetRequestTarget(), "/a/b?c=d", "incorrect derived request target")
+
+	options := request.WithMethod("OPTIONS").WithURI(gsr7.ParseURI("http://example.com"))
+	assertEquals(t, options.GetRequestTarget(), "*", "incorrect derived request target for OPTIONS")
+	connect := request.WithMethod("CONNECT").WithURI(gsr7.ParseURI("https://example.com/"))
+	assertEquals(t, connect.GetRequestTarget(), "example.com:443", "incorrect derived request target for CONNECT")
+
+	overridden, err := request.WithRequestTargetString("http://other.example.com/x?y")
+	if err != nil {
+		t.Fatalf("failed to set request target (%v)", err)
+	}
+	assertEquals(t, overridden.GetRequestTarget(), "http://other.example.com/x?y", "the request target was not set")
+	assertEquals(t, overridden.GetURI().String(), "http://other.example.com/x?y", "the URI was not updated")
+	assertEquals(t, overridden.GetHeaderLine("Host"), "other.example.com", "the Host header was not updated")
+	assertEquals(t, request.GetRequestTarget(), "/a/b?c=d", "the original request was modified")
+
+	overridden, err = request.WithMethod("OPTIONS").WithRequestTargetString("*")
+	if err != nil {
+		t.Fatalf("failed to set request target (%v)", err)
+	}
+	assertEquals(t, overridden.GetRequestTarget(), "*", "the request target was not overridden")
+	overridden = overridden.WithURI(gsr7.ParseURI("http://example.com/other"))
+	assertEquals(t, overridden.GetRequestTarget(), "*", "the overridden request target changed with the URI")
+
+	for _, target := range []string{"", "/a b", "/a\r\n", "*", "/a#b"} {
+		if _, err := request.WithRequestTargetString(target); err == nil {
+			t.Fatalf("the invalid request target %q did not result in an error", target)
+		}
+	}
+}
+
+func TestClientRequestHeaders(t *testing.T) {
+	original := gsr7.NewClientRequest("GET", gsr7.ParseURI("http://example.com/"))
+	request := original.
+		WithHeader("Accept", "text/html").
+		WithAddedHeader("accept", "application/json").
+		WithHeaderValues("X-List", []string{"a", "b"})
+	assertEquals(t, request.GetHeaderLine("ACCEPT"), "text/html, application/json", "incorrect header line")
+	assertEquals(t, request.WithoutHeader("Accept").HasHeader("Accept"), false, "header not removed")
+	assertEquals(t, original.HasHeader("Accept"), false, "the original request was modified")
+	if _, err := request.WithHeaderE("X-Bad", "a\r\nInjected: 1"); err == nil {
+		t.Fatalf("header injection did not result in an error")
+	}
+}
+
+func TestClientRequestCookies(t *testing.T) {
+	first, _ := gsr7.NewRequestCookie("first")
+	second, _ := gsr7.NewRequestCookie("second")
+	request := gsr7.NewClientRequest("GET", gsr7.ParseURI("http://example.com/")).
+		WithCookie(first.WithValue("1")).
+		WithCookie(second.WithValue("a b"))
+	assertEquals(t, request.GetHeaderLine("Cookie"), "first=1; second=a+b", "incorrect Cookie header")
+	cookies := request.GetCookies()
+	assertEquals(t, len(cookies), 2, "incorrect number of cookies")
+	assertEquals(t, cookies[1].Name(), "second", "incorrect cookie name")
+	assertEquals(t, cookies[1].Value(), "a b", "incorrect cookie value")
+	assertEquals(t, request.WithCookies(nil).HasHeader("Cookie"), false, "cookies not removed")
+}
+
+func TestClientRequestTrailers(t *testing.T) {
+	original := gsr7.NewClientRequest("POST", gsr7.ParseURI("http://example.com/upload"))
+	request := original.WithDeclaredTrailers("Content-Digest").WithTrailer("Content-Digest", "sha-256=:abc=:")
+	assertEquals(t, original.GetTrailers().Len(), 0, "original request modified")
+	assertEquals(t, request.GetHeaderLine("Trailer"), "Content-Digest", "incorrect Trailer header")
+
+	body := gsr7.NewWritableBuffer()
+	_, _ = body.Write([]byte("abc"))
+	copied := request.WithBody(body).WithHeader("X-Test", "1")
+	assertEquals(t, copied.GetTrailers().GetHeaderLine("Content-Digest"), "sha-256=:abc=:", "trailer not copied")
+	assertEquals(t, copied.GetHeaderLine("Trailer"), "Content-Digest", "Trailer header not copied")
+
+	if _, err := request.WithTrailerE("Host", "example.org"); err == nil {
+		t.Fatalf("the forbidden trailer Host did not result in an error")
+	}
+}
+
+func TestClientRequestProtocolVersionAndBody(t *testing.T) {
+	request := gsr7.NewClientRequest("GET", gsr7.ParseURI("http://example.com/"))
+	assertEquals(t, request.GetProtocolVersion().Equals(gsr7.HTTP11), true, "incorrect default version")
+	assertEquals(t, request.WithProtocolVersion(gsr7.HTTP20).GetProtocolVersion().Equals(gsr7.HTTP20), true, "version not set")
+	if request.GetBody() != nil {
+		t.Fatalf("a new request has a body")
+	}
+}
+
+//endregion
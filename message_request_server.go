@@ -1,5 +1,359 @@
 package gsr7
 
+import (
+	"crypto/tls"
+	"net/netip"
+)
+
+//region Interface
+
+// ServerParams describes the connection a server request was received on.
+type ServerParams struct {
+	// RemoteAddr is the address of the client. It is the zero value if the address is not known.
+	RemoteAddr netip.AddrPort
+	// LocalAddr is the address the request was received on. It is the zero value if the address is not known.
+	LocalAddr netip.AddrPort
+	// TLS holds the state of the TLS connection, or nil if the request was not received over TLS.
+	TLS *tls.ConnectionState
+}
+
+// ServerRequest is a request received by a HTTP server. In addition to the request itself, it carries the data a
+// server derives from it: the connection parameters, the query parameters, the parsed body, uploaded files and
+// attributes attached by the application, such as the route parameters or the authenticated user.
+//
+// The server adapter that creates the request is responsible for filling the parsed body and the uploaded files.
 type ServerRequest interface {
-    Request[ServerRequest, ReadableStream]
+	Request[ServerRequest, ReadableStream]
+
+	// GetServerParams returns the parameters of the connection the request was received on.
+	GetServerParams() ServerParams
+
+	// GetQueryParams returns the query parameters. Unless they were replaced with WithQueryParams, they are parsed
+	// from the query of the URI using the application/x-www-form-urlencoded rules.
+	GetQueryParams() Query
+	// WithQueryParams returns a copy of the request with the query parameters replaced, for example after they were
+	// sanitized. The URI is not changed.
+	WithQueryParams(query Query) ServerRequest
+
+	// GetParsedBody returns the parsed body, or nil if no parsed body was set. For form submissions this is typically
+	// a Query, for other content types the result of deserialization.
+	GetParsedBody() any
+	// WithParsedBody returns a copy of the request with the parsed body replaced. The raw body is not changed.
+	WithParsedBody(parsedBody any) ServerRequest
+
+	// GetUploadedFiles returns a copy of the uploaded files, grouped by the name of the form field.
+	GetUploadedFiles() map[string][]UploadedFile
+	// WithUploadedFiles returns a copy of the request with the uploaded files replaced.
+	WithUploadedFiles(uploadedFiles map[string][]UploadedFile) ServerRequest
+
+	// GetAttributes returns a copy of all attributes attached to the request.
+	GetAttributes() map[string]any
+	// GetAttribute returns the attribute with the specified name. If the attribute is not set false is returned.
+	GetAttribute(name string) (any, bool)
+	// WithAttribute returns a copy of the request with the attribute set to the specified value.
+	WithAttribute(name string, value any) ServerRequest
+	// WithoutAttribute returns a copy of the request with the attribute removed.
+	WithoutAttribute(name string) ServerRequest
+}
+
+// NewServerRequest creates a HTTP/1.1 server request with the specified method, URI and connection parameters. The
+// Host header is set from the authority of the URI and the body is empty. If the method is invalid or the URI is nil
+// a panic is thrown.
+func NewServerRequest(method string, uri URI, serverParams ServerParams) ServerRequest {
+	return Must(NewServerRequestE(method, uri, serverParams))
+}
+
+// NewServerRequestE creates a HTTP/1.1 server request with the specified method, URI and connection parameters. The
+// Host header is set from the authority of the URI and the body is empty. If the method is not a valid token or the
+// URI is nil an error is returned.
+func NewServerRequestE(method string, uri URI, serverParams ServerParams) (ServerRequest, error) {
+	if err := validate(validateMethod(method), validateRequestURI(uri)); err != nil {
+		return nil, err
+	}
+	r := &serverRequest{
+		message: message{
+			protocolVersion: HTTP11,
+		},
+		method:       method,
+		uri:          uri,
+		body:         NewReadableStream(nil),
+		serverParams: serverParams,
+	}
+	r.headers = withHostFromURI(r.headers, uri, false)
+	return r, nil
+}
+
+//endregion
+
+//region Implementation
+
+type serverRequest struct {
+	message
+	method string
+	uri    URI
+	// requestTarget overrides the request target derived from the method and the URI if it is not nil.
+	requestTarget RequestTarget
+	body          ReadableStream
+	serverParams  ServerParams
+	// queryParams overrides the query parameters parsed from the URI if it is not nil.
+	queryParams   Query
+	parsedBody    any
+	uploadedFiles map[string][]UploadedFile
+	attributes    map[string]any
+}
+
+func (r serverRequest) WithProtocolVersion(version Version) ServerRequest {
+	r.protocolVersion = version
+	return &r
+}
+
+func (r serverRequest) WithHeader(name, value string) ServerRequest {
+	return Must(r.WithHeaderE(name, value))
+}
+
+func (r serverRequest) WithHeaderE(name, value string) (ServerRequest, error) {
+	headers, err := r.headers.WithHeaderE(name, value)
+	if err != nil {
+		return nil, err
+	}
+	r.headers = headers
+	return &r, nil
+}
+
+func (r serverRequest) WithHeaderValues(name string, values []string) ServerRequest {
+	return Must(r.WithHeaderValuesE(name, values))
+}
+
+func (r serverRequest) WithHeaderValuesE(name string, values []string) (ServerRequest, error) {
+	headers, err := r.headers.WithHeaderValuesE(name, values)
+	if err != nil {
+		return nil, err
+	}
+	r.headers = headers
+	return &r, nil
+}
+
+func (r serverRequest) WithAddedHeader(name, value string) ServerRequest {
+	return Must(r.WithAddedHeaderE(name, value))
+}
+
+func (r serverRequest) WithAddedHeaderE(name, value string) (ServerRequest, error) {
+	headers, err := r.headers.WithAddedHeaderE(name, value)
+	if err != nil {
+		return nil, err
+	}
+	r.headers = headers
+	return &r, nil
+}
+
+func (r serverRequest) WithoutHeader(name string) ServerRequest {
+	r.headers = r.headers.WithoutHeader(name)
+	return &r
+}
+
+func (r serverRequest) GetBody() ReadableStream {
+	return r.body
+}
+
+func (r serverRequest) WithBody(body ReadableStream) ServerRequest {
+	return Must(r.WithBodyE(body))
+}
+
+func (r serverRequest) WithBodyE(body ReadableStream) (ServerRequest, error) {
+	if body == nil {
+		body = NewReadableStream(nil)
+	}
+	r.body = body
+	return &r, nil
+}
+
+func (r serverRequest) GetTrailers() Headers {
+	return r.trailersOf(r.body)
+}
+
+func (r serverRequest) WithTrailer(name, value string) ServerRequest {
+	return Must(r.WithTrailerE(name, value))
+}
+
+func (r serverRequest) WithTrailerE(name, value string) (ServerRequest, error) {
+	trailers, err := r.withTrailerE(name, value)
+	if err != nil {
+		return nil, err
+	}
+	r.trailers = trailers
+	return &r, nil
+}
+
+func (r serverRequest) WithDeclaredTrailers(names ...string) ServerRequest {
+	return Must(r.WithDeclaredTrailersE(names...))
+}
+
+func (r serverRequest) WithDeclaredTrailersE(names ...string) (ServerRequest, error) {
+	headers, err := r.withDeclaredTrailersE(names)
+	if err != nil {
+		return nil, err
+	}
+	r.headers = headers
+	return &r, nil
+}
+
+func (r serverRequest) GetCookies() []RequestCookie {
+	return parseRequestCookies(r.headers.GetHeader("Cookie"))
 }
+
+func (r serverRequest) WithCookie(cookie RequestCookie) ServerRequest {
+	return r.WithCookies(append(r.GetCookies(), cookie))
+}
+
+func (r serverRequest) WithCookies(cookies []RequestCookie) ServerRequest {
+	r.headers = withRequestCookies(r.headers, cookies)
+	return &r
+}
+
+func (r serverRequest) GetRequestTarget() string {
+	return r.GetParsedRequestTarget().String()
+}
+
+func (r serverRequest) GetParsedRequestTarget() RequestTarget {
+	if r.requestTarget != nil {
+		return r.requestTarget
+	}
+	return requestTargetFor(r.method, r.uri)
+}
+
+func (r serverRequest) WithRequestTarget(requestTarget RequestTarget) ServerRequest {
+	r.requestTarget = requestTarget
+	r.uri = requestTarget.ResolveURI(r.uri)
+	r.headers = withHostFromURI(r.headers, r.uri, false)
+	return &r
+}
+
+func (r serverRequest) WithRequestTargetString(requestTarget string) (ServerRequest, error) {
+	target, err := ParseRequestTargetE(r.method, requestTarget)
+	if err != nil {
+		return nil, err
+	}
+	return r.WithRequestTarget(target), nil
+}
+
+func (r serverRequest) GetMethod() string {
+	return r.method
+}
+
+func (r serverRequest) WithMethod(method string) ServerRequest {
+	return Must(r.WithMethodE(method))
+}
+
+func (r serverRequest) WithMethodE(method string) (ServerRequest, error) {
+	if err := validate(validateMethod(method)); err != nil {
+		return nil, err
+	}
+	r.method = method
+	return &r, nil
+}
+
+func (r serverRequest) GetURI() URI {
+	return r.uri
+}
+
+func (r serverRequest) WithURI(uri URI) ServerRequest {
+	return Must(r.withURIE(uri, false))
+}
+
+func (r serverRequest) WithURIPreserveHost(uri URI) ServerRequest {
+	return Must(r.withURIE(uri, true))
+}
+
+// withURIE implements WithURI and WithURIPreserveHost. An error is returned if the URI is nil.
+func (r serverRequest) withURIE(uri URI, preserveHost bool) (ServerRequest, error) {
+	if err := validate(validateRequestURI(uri)); err != nil {
+		return nil, err
+	}
+	r.uri = uri
+	r.headers = withHostFromURI(r.headers, uri, preserveHost)
+	return &r, nil
+}
+
+func (r serverRequest) GetServerParams() ServerParams {
+	return r.serverParams
+}
+
+func (r serverRequest) GetQueryParams() Query {
+	if r.queryParams != nil {
+		return r.queryParams
+	}
+	if r.uri == nil {
+		return NewQuery()
+	}
+	query, err := ParseQueryE(r.uri.GetQuery(), QueryEncodingForm)
+	if err != nil {
+		return NewQuery()
+	}
+	return query
+}
+
+func (r serverRequest) WithQueryParams(query Query) ServerRequest {
+	if query == nil {
+		query = NewQuery()
+	}
+	r.queryParams = query
+	return &r
+}
+
+func (r serverRequest) GetParsedBody() any {
+	return r.parsedBody
+}
+
+func (r serverRequest) WithParsedBody(parsedBody any) ServerRequest {
+	r.parsedBody = parsedBody
+	return &r
+}
+
+func (r serverRequest) GetUploadedFiles() map[string][]UploadedFile {
+	return copyUploadedFiles(r.uploadedFiles)
+}
+
+func (r serverRequest) WithUploadedFiles(uploadedFiles map[string][]UploadedFile) ServerRequest {
+	r.uploadedFiles = copyUploadedFiles(uploadedFiles)
+	return &r
+}
+
+func copyUploadedFiles(uploadedFiles map[string][]UploadedFile) map[string][]UploadedFile {
+	result := make(map[string][]UploadedFile, len(uploadedFiles))
+	for name, files := range uploadedFiles {
+		result[name] = append([]UploadedFile{}, files...)
+	}
+	return result
+}
+
+func (r serverRequest) GetAttributes() map[string]any {
+	result := make(map[string]any, len(r.attributes))
+	for name, value := range r.attributes {
+		result[name] = value
+	}
+	return result
+}
+
+func (r serverRequest) GetAttribute(name string) (any, bool) {
+	value, ok := r.attributes[name]
+	return value, ok
+}
+
+func (r serverRequest) WithAttribute(name string, value any) ServerRequest {
+	attributes := r.GetAttributes()
+	attributes[name] = value
+	r.attributes = attributes
+	return &r
+}
+
+func (r serverRequest) WithoutAttribute(name string) ServerRequest {
+	if _, ok := r.attributes[name]; !ok {
+		return &r
+	}
+	attributes := r.GetAttributes()
+	delete(attributes, name)
+	r.attributes = attributes
+	return &r
+}
+
+//endregion
@@ -0,0 +1,180 @@
+package gsr7_test
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"go.debugged.it/gsr7"
+)
+
+//region Examples
+
+func ExampleNewServerRequest() {
+	request := gsr7.NewServerRequest(
+		"GET",
+		gsr7.ParseURI("https://example.com/search?q=gsr7&page=2"),
+		gsr7.ServerParams{RemoteAddr: netip.MustParseAddrPort("192.0.2.1:54321")},
+	)
+	request = request.WithAttribute("user", "alice")
+	user, _ := request.GetAttribute("user")
+	fmt.Println(request.GetServerParams().RemoteAddr.Addr(), request.GetQueryParams().GetParameterValue("q"), user)
+	// Output: 192.0.2.1 gsr7 alice
+}
+
+//endregion
+
+//region Tests
+
+func TestNewServerRequest(t *testing.T) {
+	request := gsr7.NewServerRequest("POST", gsr7.ParseURI("http://example.com/upload"), gsr7.ServerParams{})
+	assertEquals(t, request.GetMethod(), "POST", "incorrect method")
+	assertEquals(t, request.GetRequestTarget(), "/upload", "incorrect request target")
+	assertEquals(t, request.GetHeaderLine("Host"), "example.com", "incorrect Host header")
+	assertEquals(t, request.GetProtocolVersion().String(), gsr7.HTTP11.String(), "incorrect protocol version")
+	assertEquals(t, len(request.GetBody().Bytes()), 0, "body not empty")
+	assertEquals(t, request.GetParsedBody() == nil, true, "parsed body set")
+	assertEquals(t, len(request.GetUploadedFiles()), 0, "uploaded files set")
+	assertEquals(t, len(request.GetAttributes()), 0, "attributes set")
+
+	if _, err := gsr7.NewServerRequestE("GET /", gsr7.ParseURI("http://example.com/"), gsr7.ServerParams{}); err == nil {
+		t.Fatalf("the invalid method did not result in an error")
+	}
+	if _, err := gsr7.NewServerRequestE("GET", nil, gsr7.ServerParams{}); err == nil {
+		t.Fatalf("a nil URI did not result in an error")
+	}
+}
+
+func TestServerRequestWithNilURI(t *testing.T) {
+	request := gsr7.NewServerRequest("GET", gsr7.ParseURI("http://example.com/"), gsr7.ServerParams{})
+	for name, setter := range map[string]func(gsr7.URI) gsr7.ServerRequest{
+		"WithURI":             request.WithURI,
+		"WithURIPreserveHost": request.WithURIPreserveHost,
+	} {
+		t.Run(
+			name, func(t *testing.T) {
+				defer func() {
+					if recover() == nil {
+						t.Fatalf("%s with a nil URI did not panic", name)
+					}
+				}()
+				setter(nil)
+			},
+		)
+	}
+}
+
+func TestServerRequestQueryParams(t *testing.T) {
+	request := gsr7.NewServerRequest("GET", gsr7.ParseURI("http://example.com/?a=1&b=hello+world"), gsr7.ServerParams{})
+	assertEquals(t, request.GetQueryParams().GetParameterValue("b"), "hello world", "incorrect query parameter")
+
+	modified := request.WithQueryParams(gsr7.NewQuery().WithParameter("a", "2"))
+	assertEquals(t, modified.GetQueryParams().GetParameterValue("a"), "2", "query parameters not replaced")
+	assertEquals(t, modified.GetQueryParams().HasParameter("b"), false, "old query parameter kept")
+	assertEquals(t, modified.GetURI().GetQuery(), "a=1&b=hello+world", "URI modified")
+	assertEquals(t, request.GetQueryParams().GetParameterValue("a"), "1", "original request modified")
+
+	modified = modified.WithURI(gsr7.ParseURI("http://example.com/?a=3"))
+	assertEquals(t, modified.GetQueryParams().GetParameterValue("a"), "2", "query parameters reset by WithURI")
+}
+
+func TestServerRequestParsedBody(t *testing.T) {
+	request := gsr7.NewServerRequest("POST", gsr7.ParseURI("http://example.com/"), gsr7.ServerParams{})
+	modified := request.WithParsedBody(map[string]any{"name": "gsr7"})
+	assertEquals(t, request.GetParsedBody() == nil, true, "original request modified")
+	parsed, ok := modified.GetParsedBody().(map[string]any)
+	if !ok {
+		t.Fatalf("incorrect parsed body type: %T", modified.GetParsedBody())
+	}
+	assertEquals(t, parsed["name"] == "gsr7", true, "incorrect parsed body")
+}
+
+func TestServerRequestUploadedFiles(t *testing.T) {
+	file := gsr7.NewUploadedFile(gsr7.NewReadableStream([]byte("hello")), "hello.txt", "text/plain")
+	assertEquals(t, file.GetSize(), int64(5), "incorrect file size")
+	assertEquals(t, file.GetError() == nil, true, "unexpected error")
+
+	failure := errors.New("upload truncated")
+	failed := gsr7.NewFailedUploadedFile(nil, "large.bin", "application/octet-stream", failure)
+	assertEquals(t, failed.GetSize(), int64(0), "incorrect file size")
+	assertEquals(t, failed.GetError() == failure, true, "incorrect error")
+
+	empty := gsr7.NewUploadedFile(nil, "empty.txt", "text/plain")
+	assertEquals(t, empty.GetSize(), int64(0), "incorrect file size")
+	assertEquals(t, len(empty.GetStream().Bytes()), 0, "incorrect file content")
+
+	files := map[string][]gsr7.UploadedFile{"attachments": {file, failed}}
+	request := gsr7.NewServerRequest("POST", gsr7.ParseURI("http://example.com/"), gsr7.ServerParams{})
+	request = request.WithUploadedFiles(files)
+	files["attachments"][0] = nil
+	delete(files, "attachments")
+
+	result := request.GetUploadedFiles()
+	assertEquals(t, len(result["attachments"]), 2, "incorrect number of uploaded files")
+	assertEquals(t, result["attachments"][0].GetClientFilename(), "hello.txt", "incorrect file name")
+	result["attachments"][1] = nil
+	assertEquals(t, request.GetUploadedFiles()["attachments"][1].GetClientFilename(), "large.bin", "request modified")
+}
+
+func TestServerRequestAttributes(t *testing.T) {
+	request := gsr7.NewServerRequest("GET", gsr7.ParseURI("http://example.com/"), gsr7.ServerParams{})
+	first := request.WithAttribute("id", 1)
+	second := first.WithAttribute("id", 2).WithAttribute("user", "alice")
+
+	value, ok := first.GetAttribute("id")
+	assertEquals(t, ok, true, "attribute missing")
+	assertEquals(t, value == 1, true, "attribute modified by a copy")
+	value, _ = second.GetAttribute("id")
+	assertEquals(t, value == 2, true, "attribute not replaced")
+	_, ok = request.GetAttribute("id")
+	assertEquals(t, ok, false, "original request modified")
+
+	attributes := second.GetAttributes()
+	attributes["id"] = 3
+	value, _ = second.GetAttribute("id")
+	assertEquals(t, value == 2, true, "attributes modified through the returned map")
+
+	removed := second.WithoutAttribute("id")
+	_, ok = removed.GetAttribute("id")
+	assertEquals(t, ok, false, "attribute not removed")
+	_, ok = second.GetAttribute("id")
+	assertEquals(t, ok, true, "attribute removed from the original")
+	assertEquals(t, len(removed.GetAttributes()), 1, "incorrect number of attributes")
+}
+
+func TestServerRequestTrailers(t *testing.T) {
+	original := gsr7.NewServerRequest("POST", gsr7.ParseURI("http://example.com/"), gsr7.ServerParams{})
+	request := original.WithTrailer("Grpc-Status", "0")
+	assertEquals(t, original.GetTrailers().Len(), 0, "original request modified")
+
+	body := gsr7.NewChunkedReadableStream(
+		strings.NewReader("3\r\nabc\r\n0\r\nContent-Digest: sha-256=:abc=:\r\nGrpc-Status: 1\r\n\r\n"),
+		gsr7.DefaultParseLimits(),
+	)
+	_ = body.Bytes()
+	copied := request.WithBody(body).WithHeader("X-Test", "1")
+	trailers := copied.GetTrailers()
+	assertEquals(t, trailers.GetHeaderLine("Content-Digest"), "sha-256=:abc=:", "trailer of the body missing")
+	assertEquals(t, trailers.GetHeaderLine("Grpc-Status"), "0", "trailer of the request not preferred")
+	assertEquals(t, request.GetTrailers().Len(), 1, "original request modified by WithBody")
+
+	if _, err := request.WithTrailerE("Content-Type", "text/plain"); err == nil {
+		t.Fatalf("the forbidden trailer Content-Type did not result in an error")
+	}
+}
+
+func TestServerRequestServerParams(t *testing.T) {
+	params := gsr7.ServerParams{
+		RemoteAddr: netip.MustParseAddrPort("[2001:db8::1]:443"),
+		LocalAddr:  netip.MustParseAddrPort("192.0.2.10:8443"),
+	}
+	request := gsr7.NewServerRequest("GET", gsr7.ParseURI("https://example.com/"), params)
+	request = request.WithHeader("Accept", "*/*").WithMethod("HEAD")
+	assertEquals(t, request.GetServerParams().RemoteAddr, params.RemoteAddr, "remote address lost")
+	assertEquals(t, request.GetServerParams().LocalAddr, params.LocalAddr, "local address lost")
+	assertEquals(t, request.GetServerParams().TLS == nil, true, "unexpected TLS state")
+}
+
+//endregion
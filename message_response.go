@@ -1,12 +1,57 @@
 package gsr7
 
+//region Interface
+
+// Response contains the methods shared by client and server responses.
 type Response[ResponseType any, BodyType any] interface {
 	Message[ResponseType, BodyType, ResponseCookie]
 
+	// GetStatusCode returns the three-digit status code of the response.
 	GetStatusCode() uint16
+	// GetReasonPhrase returns the reason phrase of the response. It may be empty, and HTTP/2 does not transmit it at
+	// all, so it must not be used to determine the outcome of a request.
 	GetReasonPhrase() string
+	// WithStatusCode returns a copy of the response with the specified status code and the reason phrase
+	// registered in the status package. For unregistered status codes the reason phrase is empty. If the status code
+	// is outside the range 100-599 a panic is thrown.
 	WithStatusCode(code uint16) ResponseType
+	// WithStatusCodeE returns a copy of the response with the specified status code and the reason phrase
+	// registered in the status package. For unregistered status codes the reason phrase is empty. If the status code
+	// is outside the range 100-599 an error is returned.
 	WithStatusCodeE(code uint16) (ResponseType, error)
+	// WithStatus returns a copy of the response with the specified status code and a custom reason phrase. If the
+	// status code is outside the range 100-599 or the reason phrase contains control characters a panic is thrown.
 	WithStatus(code uint16, reasonPhrase string) ResponseType
+	// WithStatusE returns a copy of the response with the specified status code and a custom reason phrase. If the
+	// status code is outside the range 100-599 or the reason phrase contains control characters an error is
+	// returned.
 	WithStatusE(code uint16, reasonPhrase string) (ResponseType, error)
 }
+
+//endregion
+
+//region Implementation
+
+// statusLine holds the status line fields shared by the response implementations.
+type statusLine struct {
+	code         uint16
+	reasonPhrase string
+}
+
+func (s statusLine) GetStatusCode() uint16 {
+	return s.code
+}
+
+func (s statusLine) GetReasonPhrase() string {
+	return s.reasonPhrase
+}
+
+// newStatusLine validates the status code and the reason phrase.
+func newStatusLine(code uint16, reasonPhrase string) (statusLine, error) {
+	if err := validate(validateStatusCode(code), validateReasonPhrase(reasonPhrase)); err != nil {
+		return statusLine{}, err
+	}
+	return statusLine{code: code, reasonPhrase: reasonPhrase}, nil
+}
+
+//endregion
@@ -1,5 +1,194 @@
 package gsr7
 
+import (
+	"go.debugged.it/gsr7/status"
+)
+
+//region Interface
+
+// ClientResponse is a response that is received by a HTTP client. Its body is read by the client after receiving the
+// response.
 type ClientResponse interface {
-    Response[ClientResponse, ReadableStream]
+	Response[ClientResponse, ReadableStream]
+}
+
+// NewClientResponse creates a response as received by a transport. The reason phrase is taken as received, so it may
+// differ from the standard reason phrase. A nil body is replaced by an empty stream. If the status code is outside the
+// range 100-599 or the reason phrase is invalid a panic is thrown.
+func NewClientResponse(
+	protocolVersion Version,
+	code uint16,
+	reasonPhrase string,
+	headers Headers,
+	body ReadableStream,
+) ClientResponse {
+	return Must(NewClientResponseE(protocolVersion, code, reasonPhrase, headers, body))
 }
+
+// NewClientResponseE creates a response as received by a transport. The reason phrase is taken as received, so it may
+// differ from the standard reason phrase. A nil body is replaced by an empty stream. If the status code is outside the
+// range 100-599 or the reason phrase is invalid an error is returned.
+func NewClientResponseE(
+	protocolVersion Version,
+	code uint16,
+	reasonPhrase string,
+	headers Headers,
+	body ReadableStream,
+) (ClientResponse, error) {
+	s, err := newStatusLine(code, reasonPhrase)
+	if err != nil {
+		return nil, err
+	}
+	if body == nil {
+		body = NewReadableStream(nil)
+	}
+	return &clientResponse{
+		message: message{
+			protocolVersion: protocolVersion,
+			headers:         headers,
+		},
+		statusLine: s,
+		body:       body,
+	}, nil
+}
+
+//endregion
+
+//region Implementation
+
+type clientResponse struct {
+	message
+	statusLine
+	body ReadableStream
+}
+
+func (r clientResponse) WithProtocolVersion(version Version) ClientResponse {
+	r.protocolVersion = version
+	return &r
+}
+
+func (r clientResponse) WithHeader(name, value string) ClientResponse {
+	return Must(r.WithHeaderE(name, value))
+}
+
+func (r clientResponse) WithHeaderE(name, value string) (ClientResponse, error) {
+	headers, err := r.headers.WithHeaderE(name, value)
+	if err != nil {
+		return nil, err
+	}
+	r.headers = headers
+	return &r, nil
+}
+
+func (r clientResponse) WithHeaderValues(name string, values []string) ClientResponse {
+	return Must(r.WithHeaderValuesE(name, values))
+}
+
+func (r clientResponse) WithHeaderValuesE(name string, values []string) (ClientResponse, error) {
+	headers, err := r.headers.WithHeaderValuesE(name, values)
+	if err != nil {
+		return nil, err
+	}
+	r.headers = headers
+	return &r, nil
+}
+
+func (r clientResponse) WithAddedHeader(name, value string) ClientResponse {
+	return Must(r.WithAddedHeaderE(name, value))
+}
+
+func (r clientResponse) WithAddedHeaderE(name, value string) (ClientResponse, error) {
+	headers, err := r.headers.WithAddedHeaderE(name, value)
+	if err != nil {
+		return nil, err
+	}
+	r.headers = headers
+	return &r, nil
+}
+
+func (r clientResponse) WithoutHeader(name string) ClientResponse {
+	r.headers = r.headers.WithoutHeader(name)
+	return &r
+}
+
+func (r clientResponse) GetBody() ReadableStream {
+	return r.body
+}
+
+func (r clientResponse) WithBody(body ReadableStream) ClientResponse {
+	return Must(r.WithBodyE(body))
+}
+
+func (r clientResponse) WithBodyE(body ReadableStream) (ClientResponse, error) {
+	if body == nil {
+		body = NewReadableStream(nil)
+	}
+	r.body = body
+	return &r, nil
+}
+
+func (r clientResponse) GetTrailers() Headers {
+	return r.trailersOf(r.body)
+}
+
+func (r clientResponse) WithTrailer(name, value string) ClientResponse {
+	return Must(r.WithTrailerE(name, value))
+}
+
+func (r clientResponse) WithTrailerE(name, value string) (ClientResponse, error) {
+	trailers, err := r.withTrailerE(name, value)
+	if err != nil {
+		return nil, err
+	}
+	r.trailers = trailers
+	return &r, nil
+}
+
+func (r clientResponse) WithDeclaredTrailers(names ...string) ClientResponse {
+	return Must(r.WithDeclaredTrailersE(names...))
+}
+
+func (r clientResponse) WithDeclaredTrailersE(names ...string) (ClientResponse, error) {
+	headers, err := r.withDeclaredTrailersE(names)
+	if err != nil {
+		return nil, err
+	}
+	r.headers = headers
+	return &r, nil
+}
+
+func (r clientResponse) GetCookies() []ResponseCookie {
+	return parseResponseCookies(r.headers.GetHeader("Set-Cookie"))
+}
+
+func (r clientResponse) WithCookie(cookie ResponseCookie) ClientResponse {
+	return r.WithCookies(append(r.GetCookies(), cookie))
+}
+
+func (r clientResponse) WithCookies(cookies []ResponseCookie) ClientResponse {
+	r.headers = withResponseCookies(r.headers, cookies)
+	return &r
+}
+
+func (r clientResponse) WithStatusCode(code uint16) ClientResponse {
+	return Must(r.WithStatusCodeE(code))
+}
+
+func (r clientResponse) WithStatusCodeE(code uint16) (ClientResponse, error) {
+	return r.WithStatusE(code, status.ReasonPhrase(code))
+}
+
+func (r clientResponse) WithStatus(code uint16, reasonPhrase string) ClientResponse {
+	return Must(r.WithStatusE(code, reasonPhrase))
+}
+
+func (r clientResponse) WithStatusE(code uint16, reasonPhrase string) (ClientResponse, error) {
+	s, err := newStatusLine(code, reasonPhrase)
+	if err != nil {
+		return nil, err
+	}
+	r.statusLine = s
+	return &r, nil
+}
+
+//endregion
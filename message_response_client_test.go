@@ -0,0 +1,101 @@
+package gsr7_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"go.debugged.it/gsr7"
+)
+
+//region Examples
+
+func ExampleNewClientResponse() {
+	headers := gsr7.NewHeaders().WithHeader("Content-Type", "text/plain")
+	response := gsr7.NewClientResponse(gsr7.HTTP11, 200, "Okay", headers, gsr7.NewReadableStream([]byte("hello")))
+	fmt.Println(response.GetStatusCode(), response.GetReasonPhrase(), response.GetHeaderLine("Content-Type"))
+	fmt.Println(response.GetBody().String())
+	// Output: 200 Okay text/plain
+	// hello
+}
+
+//endregion
+
+//region Tests
+
+func TestNewClientResponse(t *testing.T) {
+	response := gsr7.NewClientResponse(gsr7.HTTP10, 404, "Gone Fishing", gsr7.NewHeaders(), nil)
+	assertEquals(t, response.GetProtocolVersion().String(), gsr7.HTTP10.String(), "incorrect protocol version")
+	assertEquals(t, response.GetStatusCode(), uint16(404), "incorrect status code")
+	assertEquals(t, response.GetReasonPhrase(), "Gone Fishing", "received reason phrase not kept")
+	assertEquals(t, len(response.GetBody().Bytes()), 0, "body not empty")
+	assertEquals(t, len(response.GetHeaders()), 0, "headers set")
+
+	for _, code := range []uint16{0, 99, 600} {
+		if _, err := gsr7.NewClientResponseE(gsr7.HTTP11, code, "", gsr7.NewHeaders(), nil); err == nil {
+			t.Fatalf("the invalid status code %d did not result in an error", code)
+		}
+	}
+	if _, err := gsr7.NewClientResponseE(gsr7.HTTP11, 200, "OK\r\nX-Injected: 1", gsr7.NewHeaders(), nil); err == nil {
+		t.Fatalf("a reason phrase with a line break did not result in an error")
+	}
+}
+
+func TestClientResponseStatus(t *testing.T) {
+	response := gsr7.NewClientResponse(gsr7.HTTP11, 200, "Fine", gsr7.NewHeaders(), nil)
+	modified := response.WithStatusCode(429)
+	assertEquals(t, modified.GetStatusCode(), uint16(429), "incorrect status code")
+	assertEquals(t, modified.GetReasonPhrase(), "Too Many Requests", "standard reason phrase not set")
+	assertEquals(t, response.GetReasonPhrase(), "Fine", "original response modified")
+
+	if _, err := response.WithStatusCodeE(600); err == nil {
+		t.Fatalf("the invalid status code did not result in an error")
+	}
+	if _, err := response.WithStatusE(200, "Fine\nX-Injected: 1"); err == nil {
+		t.Fatalf("a reason phrase with a line break did not result in an error")
+	}
+}
+
+func TestClientResponseImmutability(t *testing.T) {
+	original := gsr7.NewClientResponse(gsr7.HTTP11, 200, "OK", gsr7.NewHeaders(), gsr7.NewReadableStream([]byte("a")))
+	modified := original.
+		WithHeader("Content-Type", "text/plain").
+		WithProtocolVersion(gsr7.HTTP10).
+		WithBody(gsr7.NewReadableStream([]byte("b")))
+	assertEquals(t, original.HasHeader("Content-Type"), false, "original response modified")
+	assertEquals(t, original.GetProtocolVersion().String(), gsr7.HTTP11.String(), "original version modified")
+	assertEquals(t, original.GetBody().String(), "a", "original body modified")
+	assertEquals(t, modified.GetHeaderLine("content-type"), "text/plain", "header not set")
+	assertEquals(t, modified.GetProtocolVersion().String(), gsr7.HTTP10.String(), "protocol version not set")
+	assertEquals(t, modified.GetBody().String(), "b", "body not set")
+	assertEquals(t, modified.WithBody(nil).GetBody().String(), "", "nil body not replaced by an empty stream")
+}
+
+func TestClientResponseCookies(t *testing.T) {
+	headers := gsr7.NewHeaders().
+		WithAddedHeader("Set-Cookie", "session=abc; Path=/; Secure").
+		WithAddedHeader("Set-Cookie", "theme=dark")
+	response := gsr7.NewClientResponse(gsr7.HTTP11, 200, "OK", headers, nil)
+	cookies := response.GetCookies()
+	assertEquals(t, len(cookies), 2, "incorrect number of cookies")
+	assertEquals(t, cookies[0].Name(), "session", "incorrect cookie name")
+	assertEquals(t, cookies[0].GetPath(), "/", "incorrect cookie path")
+	assertEquals(t, cookies[0].GetSecure(), true, "secure flag lost")
+	assertEquals(t, cookies[1].Value(), "dark", "incorrect cookie value")
+
+	response = response.WithCookies(nil)
+	assertEquals(t, response.HasHeader("Set-Cookie"), false, "Set-Cookie header not removed")
+}
+
+func TestClientResponseTrailers(t *testing.T) {
+	body := gsr7.NewChunkedReadableStream(
+		strings.NewReader("2\r\nok\r\n0\r\nGrpc-Status: 0\r\nContent-Type: text/plain\r\n\r\n"),
+		gsr7.DefaultParseLimits(),
+	)
+	response := gsr7.NewClientResponse(gsr7.HTTP11, 200, "OK", gsr7.NewHeaders(), body)
+	assertEquals(t, response.GetBody().String(), "ok", "incorrect body")
+	assertEquals(t, response.GetTrailers().GetHeaderLine("Grpc-Status"), "0", "trailer of the body missing")
+	assertEquals(t, response.GetTrailers().HasHeader("Content-Type"), false, "forbidden trailer not omitted")
+}
+
+//endregion
@@ -1,5 +1,172 @@
 package gsr7
 
+import (
+	"go.debugged.it/gsr7/status"
+)
+
+//region Interface
+
+// ServerResponse is a response that is sent by a HTTP server. Its body is written by the server when sending the
+// response.
 type ServerResponse interface {
-    Response[ServerResponse, WritableStream]
+	Response[ServerResponse, WritableStream]
+}
+
+// NewServerResponse creates a HTTP/1.1 response with the specified status code and its standard reason phrase. The
+// response has no headers and no body. If the status code is outside the range 100-599 a panic is thrown.
+func NewServerResponse(code uint16) ServerResponse {
+	return Must(NewServerResponseE(code))
 }
+
+// NewServerResponseE creates a HTTP/1.1 response with the specified status code and its standard reason phrase. The
+// response has no headers and no body. If the status code is outside the range 100-599 an error is returned.
+func NewServerResponseE(code uint16) (ServerResponse, error) {
+	s, err := newStatusLine(code, status.ReasonPhrase(code))
+	if err != nil {
+		return nil, err
+	}
+	return &serverResponse{
+		message: message{
+			protocolVersion: HTTP11,
+		},
+		statusLine: s,
+	}, nil
+}
+
+//endregion
+
+//region Implementation
+
+type serverResponse struct {
+	message
+	statusLine
+	body WritableStream
+}
+
+func (r serverResponse) WithProtocolVersion(version Version) ServerResponse {
+	r.protocolVersion = version
+	return &r
+}
+
+func (r serverResponse) WithHeader(name, value string) ServerResponse {
+	return Must(r.WithHeaderE(name, value))
+}
+
+func (r serverResponse) WithHeaderE(name, value string) (ServerResponse, error) {
+	headers, err := r.headers.WithHeaderE(name, value)
+	if err != nil {
+		return nil, err
+	}
+	r.headers = headers
+	return &r, nil
+}
+
+func (r serverResponse) WithHeaderValues(name string, values []string) ServerResponse {
+	return Must(r.WithHeaderValuesE(name, values))
+}
+
+func (r serverResponse) WithHeaderValuesE(name string, values []string) (ServerResponse, error) {
+	headers, err := r.headers.WithHeaderValuesE(name, values)
+	if err != nil {
+		return nil, err
+	}
+	r.headers = headers
+	return &r, nil
+}
+
+func (r serverResponse) WithAddedHeader(name, value string) ServerResponse {
+	return Must(r.WithAddedHeaderE(name, value))
+}
+
+func (r serverResponse) WithAddedHeaderE(name, value string) (ServerResponse, error) {
+	headers, err := r.headers.WithAddedHeaderE(name, value)
+	if err != nil {
+		return nil, err
+	}
+	r.headers = headers
+	return &r, nil
+}
+
+func (r serverResponse) WithoutHeader(name string) ServerResponse {
+	r.headers = r.headers.WithoutHeader(name)
+	return &r
+}
+
+func (r serverResponse) GetBody() WritableStream {
+	return r.body
+}
+
+func (r serverResponse) WithBody(body WritableStream) ServerResponse {
+	return Must(r.WithBodyE(body))
+}
+
+func (r serverResponse) WithBodyE(body WritableStream) (ServerResponse, error) {
+	r.body = body
+	return &r, nil
+}
+
+func (r serverResponse) GetTrailers() Headers {
+	return r.trailersOf(r.body)
+}
+
+func (r serverResponse) WithTrailer(name, value string) ServerResponse {
+	return Must(r.WithTrailerE(name, value))
+}
+
+func (r serverResponse) WithTrailerE(name, value string) (ServerResponse, error) {
+	trailers, err := r.withTrailerE(name, value)
+	if err != nil {
+		return nil, err
+	}
+	r.trailers = trailers
+	return &r, nil
+}
+
+func (r serverResponse) WithDeclaredTrailers(names ...string) ServerResponse {
+	return Must(r.WithDeclaredTrailersE(names...))
+}
+
+func (r serverResponse) WithDeclaredTrailersE(names ...string) (ServerResponse, error) {
+	headers, err := r.withDeclaredTrailersE(names)
+	if err != nil {
+		return nil, err
+	}
+	r.headers = headers
+	return &r, nil
+}
+
+func (r serverResponse) GetCookies() []ResponseCookie {
+	return parseResponseCookies(r.headers.GetHeader("Set-Cookie"))
+}
+
+func (r serverResponse) WithCookie(cookie ResponseCookie) ServerResponse {
+	return r.WithCookies(append(r.GetCookies(), cookie))
+}
+
+func (r serverResponse) WithCookies(cookies []ResponseCookie) ServerResponse {
+	r.headers = withResponseCookies(r.headers, cookies)
+	return &r
+}
+
+func (r serverResponse) WithStatusCode(code uint16) ServerResponse {
+	return Must(r.WithStatusCodeE(code))
+}
+
+func (r serverResponse) WithStatusCodeE(code uint16) (ServerResponse, error) {
+	return r.WithStatusE(code, status.ReasonPhrase(code))
+}
+
+func (r serverResponse) WithStatus(code uint16, reasonPhrase string) ServerResponse {
+	return Must(r.WithStatusE(code, reasonPhrase))
+}
+
+func (r serverResponse) WithStatusE(code uint16, reasonPhrase string) (ServerResponse, error) {
+	s, err := newStatusLine(code, reasonPhrase)
+	if err != nil {
+		return nil, err
+	}
+	r.statusLine = s
+	return &r, nil
+}
+
+//endregion
@@ -0,0 +1,132 @@
+package gsr7_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"go.debugged.it/gsr7"
+)
+
+//region Examples
+
+func ExampleNewServerResponse() {
+	response := gsr7.NewServerResponse(404)
+	fmt.Println(response.GetProtocolVersion(), response.GetStatusCode(), response.GetReasonPhrase())
+	response = response.WithStatusCode(200)
+	fmt.Println(response.GetStatusCode(), response.GetReasonPhrase())
+	// Output: HTTP/1.1 404 Not Found
+	// 200 OK
+}
+
+//endregion
+
+//region Tests
+
+func TestServerResponseStatus(t *testing.T) {
+	testData := []struct {
+		code         uint16
+		reasonPhrase string
+	}{
+		{100, "Continue"},
+		{204, "No Content"},
+		{207, "Multi-Status"},
+		{308, "Permanent Redirect"},
+		{418, ""},
+		{429, "Too Many Requests"},
+		{599, ""},
+	}
+	for _, testCase := range testData {
+		t.Run(
+			fmt.Sprintf("%d", testCase.code), func(t *testing.T) {
+				response := gsr7.NewServerResponse(200).WithStatusCode(testCase.code)
+				assertEquals(t, response.GetStatusCode(), testCase.code, "incorrect status code")
+				assertEquals(t, response.GetReasonPhrase(), testCase.reasonPhrase, "incorrect reason phrase")
+			},
+		)
+	}
+
+	for _, code := range []uint16{0, 99, 600, 999} {
+		if _, err := gsr7.NewServerResponseE(code); err == nil {
+			t.Fatalf("the invalid status code %d did not result in an error", code)
+		}
+		if _, err := gsr7.NewServerResponse(200).WithStatusCodeE(code); err == nil {
+			t.Fatalf("the invalid status code %d did not result in an error", code)
+		}
+	}
+
+	response := gsr7.NewServerResponse(200).WithStatus(200, "Fine")
+	assertEquals(t, response.GetReasonPhrase(), "Fine", "custom reason phrase not set")
+	if _, err := response.WithStatusE(200, "Fine\r\nX-Injected: 1"); err == nil {
+		t.Fatalf("a reason phrase with a line break did not result in an error")
+	}
+}
+
+func TestServerResponseImmutability(t *testing.T) {
+	original := gsr7.NewServerResponse(200)
+	modified := original.WithHeader("Content-Type", "text/plain").WithStatusCode(201)
+	assertEquals(t, original.HasHeader("Content-Type"), false, "original response modified")
+	assertEquals(t, original.GetStatusCode(), uint16(200), "original status code modified")
+	assertEquals(t, modified.GetHeaderLine("content-type"), "text/plain", "header not set")
+	assertEquals(t, modified.GetStatusCode(), uint16(201), "status code not set")
+}
+
+func TestServerResponseCookies(t *testing.T) {
+	response := gsr7.NewServerResponse(200).
+		WithCookie(gsr7.NewResponseCookie("session").WithValue("abc").WithPath("/").WithSecure(true)).
+		WithCookie(gsr7.NewResponseCookie("theme").WithValue("dark"))
+	assertEquals(t, len(response.GetHeader("Set-Cookie")), 2, "incorrect number of Set-Cookie headers")
+
+	cookies := response.GetCookies()
+	assertEquals(t, len(cookies), 2, "incorrect number of cookies")
+	assertEquals(t, cookies[0].Name(), "session", "incorrect cookie name")
+	assertEquals(t, cookies[0].Value(), "abc", "incorrect cookie value")
+	assertEquals(t, cookies[0].GetPath(), "/", "incorrect cookie path")
+	assertEquals(t, cookies[0].GetSecure(), true, "secure flag lost")
+	assertEquals(t, cookies[1].Name(), "theme", "incorrect cookie name")
+
+	response = response.WithHeader(
+		"Set-Cookie",
+		"id=a3fWa; Expires=Wed, 21 Oct 2015 07:28:00 GMT; Max-Age=60; Domain=example.com; HttpOnly; SameSite=Lax",
+	)
+	cookies = response.GetCookies()
+	assertEquals(t, len(cookies), 1, "incorrect number of cookies")
+	cookie := cookies[0]
+	assertEquals(t, cookie.GetDomain(), "example.com", "incorrect domain")
+	assertEquals(t, cookie.GetHTTPOnly(), true, "httpOnly flag lost")
+	assertEquals(t, *cookie.GetMaxAge(), 60, "incorrect max-age")
+	assertEquals(t, cookie.GetExpires().Equal(time.Date(2015, 10, 21, 7, 28, 0, 0, time.UTC)), true, "incorrect expires")
+	assertEquals(t, len(cookie.GetExtensions()), 1, "incorrect number of extensions")
+	assertEquals(t, cookie.GetExtensions()[0], "SameSite=Lax", "incorrect extension")
+
+	response = response.WithCookies(nil)
+	assertEquals(t, response.HasHeader("Set-Cookie"), false, "Set-Cookie header not removed")
+}
+
+func TestServerResponseTrailers(t *testing.T) {
+	original := gsr7.NewServerResponse(200)
+	response := original.
+		WithDeclaredTrailers("Content-Digest", "Grpc-Status").
+		WithTrailer("Content-Digest", "sha-256=:abc=:").
+		WithTrailer("grpc-status", "0")
+	assertEquals(t, original.GetTrailers().Len(), 0, "original response modified")
+	assertEquals(t, response.GetHeaderLine("Trailer"), "Content-Digest, Grpc-Status", "incorrect Trailer header")
+	assertEquals(t, response.GetTrailers().GetHeaderLine("Grpc-Status"), "0", "incorrect trailer")
+	assertEquals(t, response.GetTrailers().Len(), 2, "incorrect number of trailers")
+	assertEquals(t, response.WithDeclaredTrailers().HasHeader("Trailer"), false, "Trailer header not removed")
+
+	forbidden := []string{"Content-Length", "transfer-encoding", "Host", "Authorization", "Set-Cookie", "Trailer"}
+	for _, name := range forbidden {
+		if _, err := response.WithTrailerE(name, "1"); err == nil {
+			t.Fatalf("the forbidden trailer %s did not result in an error", name)
+		}
+		if _, err := response.WithDeclaredTrailersE(name); err == nil {
+			t.Fatalf("declaring the forbidden trailer %s did not result in an error", name)
+		}
+	}
+	if _, err := response.WithTrailerE("X-Test", "a\r\nb"); err == nil {
+		t.Fatalf("a trailer value with a line break did not result in an error")
+	}
+}
+
+//endregion
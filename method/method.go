@@ -0,0 +1,229 @@
+// Package method contains the HTTP method registry. It covers all methods registered with IANA, including the WebDAV
+// extensions, and allows registering further methods. Method names are case-sensitive: "get" is a valid method, but
+// it is not the same method as "GET".
+//
+// See https://www.iana.org/assignments/http-methods/http-methods.xhtml for details.
+package method
+
+import (
+	"fmt"
+	"sync"
+)
+
+//region Constants
+
+// Methods defined in RFC 9110 and RFC 5789.
+const (
+	Connect = "CONNECT"
+	Delete  = "DELETE"
+	Get     = "GET"
+	Head    = "HEAD"
+	Options = "OPTIONS"
+	Patch   = "PATCH"
+	Post    = "POST"
+	Put     = "PUT"
+	Trace   = "TRACE"
+)
+
+// Methods defined by WebDAV and its extensions.
+const (
+	ACL               = "ACL"
+	BaselineControl   = "BASELINE-CONTROL"
+	Bind              = "BIND"
+	Checkin           = "CHECKIN"
+	Checkout          = "CHECKOUT"
+	Copy              = "COPY"
+	Label             = "LABEL"
+	Lock              = "LOCK"
+	Merge             = "MERGE"
+	MkActivity        = "MKACTIVITY"
+	MkCalendar        = "MKCALENDAR"
+	MkCol             = "MKCOL"
+	MkRedirectRef     = "MKREDIRECTREF"
+	MkWorkspace       = "MKWORKSPACE"
+	Move              = "MOVE"
+	OrderPatch        = "ORDERPATCH"
+	PropFind          = "PROPFIND"
+	PropPatch         = "PROPPATCH"
+	Rebind            = "REBIND"
+	Report            = "REPORT"
+	Search            = "SEARCH"
+	Unbind            = "UNBIND"
+	Uncheckout        = "UNCHECKOUT"
+	Unlock            = "UNLOCK"
+	Update            = "UPDATE"
+	UpdateRedirectRef = "UPDATEREDIRECTREF"
+	VersionControl    = "VERSION-CONTROL"
+)
+
+// Other registered methods.
+const (
+	// Link and Unlink are defined in an expired draft, but remain registered.
+	Link   = "LINK"
+	Unlink = "UNLINK"
+	// Pri is the start of the HTTP/2 connection preface and must not be used as a method.
+	Pri = "PRI"
+)
+
+//endregion
+
+//region Interface
+
+// Method describes the properties of a request method.
+//
+// See https://datatracker.ietf.org/doc/html/rfc9110#section-9.2 for details.
+type Method struct {
+	// Name is the case-sensitive name of the method.
+	Name string
+	// Safe is true if the method is essentially read-only, so the client does not request a state change on the
+	// server. Safe methods can be prefetched and need no CSRF protection.
+	Safe bool
+	// Idempotent is true if sending the request multiple times has the same effect as sending it once. Requests with
+	// idempotent methods can be retried automatically. Safe methods are always idempotent.
+	Idempotent bool
+	// Cacheable is true if responses to the method may be stored by caches.
+	Cacheable bool
+}
+
+// Validate checks that the name is a valid method, which is a non-empty token. If it is not an error is returned.
+//
+// See https://datatracker.ietf.org/doc/html/rfc9110#section-9.1 for details.
+func Validate(name string) error {
+	if name == "" {
+		return fmt.Errorf("empty method")
+	}
+	for i := 0; i < len(name); i++ {
+		if !isTokenChar(name[i]) {
+			return fmt.Errorf("invalid character in method position %d (%d)", i, name[i])
+		}
+	}
+	return nil
+}
+
+// Lookup returns the description of a registered method. The name is matched case-sensitively. If the method is not
+// registered false is returned.
+func Lookup(name string) (Method, bool) {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+	m, ok := registry[name]
+	return m, ok
+}
+
+// RegisterMethod adds a method to the registry. A custom method can be registered again to change its description.
+// An error is returned if the name is not a valid token, if the method is registered with IANA, or if the method is
+// marked as safe but not as idempotent.
+func RegisterMethod(m Method) error {
+	if err := Validate(m.Name); err != nil {
+		return err
+	}
+	if _, ok := ianaRegistry[m.Name]; ok {
+		return fmt.Errorf("the method %s is registered with IANA and cannot be changed", m.Name)
+	}
+	if m.Safe && !m.Idempotent {
+		return fmt.Errorf("the method %s is safe, but not idempotent", m.Name)
+	}
+	registryLock.Lock()
+	defer registryLock.Unlock()
+	registry[m.Name] = m
+	return nil
+}
+
+// IsSafe returns true if the method is registered as safe. Unregistered methods are not safe.
+func IsSafe(name string) bool {
+	m, _ := Lookup(name)
+	return m.Safe
+}
+
+// IsIdempotent returns true if the method is registered as idempotent. Unregistered methods are not idempotent.
+func IsIdempotent(name string) bool {
+	m, _ := Lookup(name)
+	return m.Idempotent
+}
+
+// IsCacheable returns true if responses to the method may be stored by caches. This is the case for GET, HEAD and
+// POST, but responses to POST are only reused for later GET and HEAD requests if they carry explicit freshness
+// information and a matching Content-Location header. Unregistered methods are not cacheable.
+//
+// See https://datatracker.ietf.org/doc/html/rfc9110#section-9.2.3 for details.
+func IsCacheable(name string) bool {
+	m, _ := Lookup(name)
+	return m.Cacheable
+}
+
+//endregion
+
+//region Implementation
+
+// ianaMethods contains the methods registered with IANA. The reserved name "*" is not a valid token and therefore not
+// listed.
+var ianaMethods = []Method{
+	{Name: ACL, Idempotent: true},
+	{Name: BaselineControl, Idempotent: true},
+	{Name: Bind, Idempotent: true},
+	{Name: Checkin, Idempotent: true},
+	{Name: Checkout, Idempotent: true},
+	{Name: Connect},
+	{Name: Copy, Idempotent: true},
+	{Name: Delete, Idempotent: true},
+	{Name: Get, Safe: true, Idempotent: true, Cacheable: true},
+	{Name: Head, Safe: true, Idempotent: true, Cacheable: true},
+	{Name: Label, Idempotent: true},
+	{Name: Link, Idempotent: true},
+	{Name: Lock},
+	{Name: Merge, Idempotent: true},
+	{Name: MkActivity, Idempotent: true},
+	{Name: MkCalendar, Idempotent: true},
+	{Name: MkCol, Idempotent: true},
+	{Name: MkRedirectRef, Idempotent: true},
+	{Name: MkWorkspace, Idempotent: true},
+	{Name: Move, Idempotent: true},
+	{Name: Options, Safe: true, Idempotent: true},
+	{Name: OrderPatch, Idempotent: true},
+	{Name: Patch},
+	{Name: Post, Cacheable: true},
+	{Name: Pri, Safe: true, Idempotent: true},
+	{Name: PropFind, Safe: true, Idempotent: true},
+	{Name: PropPatch, Idempotent: true},
+	{Name: Put, Idempotent: true},
+	{Name: Rebind, Idempotent: true},
+	{Name: Report, Safe: true, Idempotent: true},
+	{Name: Search, Safe: true, Idempotent: true},
+	{Name: Trace, Safe: true, Idempotent: true},
+	{Name: Unbind, Idempotent: true},
+	{Name: Uncheckout, Idempotent: true},
+	{Name: Unlink, Idempotent: true},
+	{Name: Unlock, Idempotent: true},
+	{Name: Update, Idempotent: true},
+	{Name: UpdateRedirectRef, Idempotent: true},
+	{Name: VersionControl, Idempotent: true},
+}
+
+var (
+	ianaRegistry = indexMethods(ianaMethods)
+	// registry contains the IANA methods and the methods added with RegisterMethod.
+	registry     = indexMethods(ianaMethods)
+	registryLock sync.RWMutex
+)
+
+// isTokenChar checks for the tchar characters in RFC 9110 section 5.6.2.
+func isTokenChar(c byte) bool {
+	switch {
+	case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		return true
+	}
+	switch c {
+	case '!', '#', '$', '%', '&', '\'', '*', '+', '-', '.', '^', '_', '`', '|', '~':
+		return true
+	}
+	return false
+}
+
+func indexMethods(methods []Method) map[string]Method {
+	result := make(map[string]Method, len(methods))
+	for _, m := range methods {
+		result[m.Name] = m
+	}
+	return result
+}
+
+//endregion
@@ -0,0 +1,110 @@
+package method_test
+
+import (
+	"fmt"
+	"testing"
+
+	"go.debugged.it/gsr7/method"
+)
+
+//region Examples
+
+func ExampleIsIdempotent() {
+	fmt.Println(method.IsIdempotent(method.Put), method.IsIdempotent(method.Post))
+	// Output: true false
+}
+
+func ExampleRegisterMethod() {
+	if err := method.RegisterMethod(method.Method{Name: "PURGE", Idempotent: true}); err != nil {
+		panic(err)
+	}
+	fmt.Println(method.IsSafe("PURGE"), method.IsIdempotent("PURGE"))
+	// Output: false true
+}
+
+//endregion
+
+//region Tests
+
+func TestRegistry(t *testing.T) {
+	testData := []struct {
+		name       string
+		safe       bool
+		idempotent bool
+		cacheable  bool
+	}{
+		{method.Get, true, true, true},
+		{method.Head, true, true, true},
+		{method.Post, false, false, true},
+		{method.Put, false, true, false},
+		{method.Delete, false, true, false},
+		{method.Connect, false, false, false},
+		{method.Options, true, true, false},
+		{method.Trace, true, true, false},
+		{method.Patch, false, false, false},
+		{method.PropFind, true, true, false},
+		{method.Lock, false, false, false},
+		{method.MkCol, false, true, false},
+		{method.Report, true, true, false},
+		{"get", false, false, false},
+		{"UNKNOWN", false, false, false},
+	}
+	for _, testCase := range testData {
+		t.Run(
+			testCase.name, func(t *testing.T) {
+				if method.IsSafe(testCase.name) != testCase.safe {
+					t.Fatalf("incorrect IsSafe result")
+				}
+				if method.IsIdempotent(testCase.name) != testCase.idempotent {
+					t.Fatalf("incorrect IsIdempotent result")
+				}
+				if method.IsCacheable(testCase.name) != testCase.cacheable {
+					t.Fatalf("incorrect IsCacheable result")
+				}
+			},
+		)
+	}
+	if _, ok := method.Lookup("Get"); ok {
+		t.Fatalf("the method lookup is not case-sensitive")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, name := range []string{"GET", "get", "BASELINE-CONTROL", "X_CUSTOM.1", "~!#$%&'*+^`|"} {
+		if err := method.Validate(name); err != nil {
+			t.Fatalf("the valid method %q resulted in an error (%v)", name, err)
+		}
+	}
+	for _, name := range []string{"", "GET /", "GÉT", "GET\r\n", "(GET)", "GET:", "\"GET\""} {
+		if err := method.Validate(name); err == nil {
+			t.Fatalf("the invalid method %q did not result in an error", name)
+		}
+	}
+}
+
+func TestRegisterMethod(t *testing.T) {
+	custom := method.Method{Name: "QUERY", Safe: true, Idempotent: true}
+	if err := method.RegisterMethod(custom); err != nil {
+		t.Fatalf("failed to register a custom method (%v)", err)
+	}
+	registered, ok := method.Lookup("QUERY")
+	if !ok || registered != custom {
+		t.Fatalf("the custom method was not registered: %v", registered)
+	}
+
+	for _, invalid := range []method.Method{
+		{Name: ""},
+		{Name: "NOT VALID"},
+		{Name: method.Post, Idempotent: true},
+		{Name: "PEEK", Safe: true},
+	} {
+		if err := method.RegisterMethod(invalid); err == nil {
+			t.Fatalf("registering %q did not result in an error", invalid.Name)
+		}
+	}
+	if method.IsIdempotent(method.Post) {
+		t.Fatalf("the IANA method was changed")
+	}
+}
+
+//endregion
@@ -0,0 +1,256 @@
+// Package publicsuffix answers questions about domain names based on the Public Suffix List. A public suffix is a
+// domain under which anyone can register names, such as "com", "co.uk" or "github.io". The registrable domain is the
+// public suffix plus one more label, for example "example.co.uk". User agents use this to prevent websites from
+// setting cookies for a whole public suffix ("supercookies").
+//
+// The package embeds a snapshot of the list. To update it, run go generate in this directory. Applications that need
+// a more recent list at runtime can load one with ParseList.
+//
+// See https://publicsuffix.org/ for details.
+package publicsuffix
+
+//go:generate curl -sSfLo public_suffix_list.dat https://publicsuffix.org/list/public_suffix_list.dat
+
+import (
+	"bufio"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"net/netip"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/idna"
+)
+
+//region Interface
+
+// List is a parsed Public Suffix List.
+type List interface {
+	// PublicSuffix returns the public suffix of the host. The icann flag is true if the suffix is managed by ICANN
+	// and false if it was submitted by a private party, such as "github.io", or if the host is not covered by the
+	// list and the last label is used as a fallback. An empty string is returned for IP addresses and empty hosts.
+	PublicSuffix(host string) (suffix string, icann bool)
+	// RegistrableDomain returns the public suffix of the host plus one more label. An error is returned for IP
+	// addresses, hosts with empty labels and hosts that are themselves public suffixes.
+	RegistrableDomain(host string) (string, error)
+}
+
+//go:embed public_suffix_list.dat
+var embeddedList string
+
+var (
+	defaultList     List
+	defaultListOnce sync.Once
+)
+
+// Default returns the list embedded in this package. The list is parsed on first use.
+func Default() List {
+	defaultListOnce.Do(
+		func() {
+			list, err := ParseList(strings.NewReader(embeddedList))
+			if err != nil {
+				panic(fmt.Errorf("failed to parse the embedded public suffix list (%w)", err))
+			}
+			defaultList = list
+		},
+	)
+	return defaultList
+}
+
+// ParseList parses a list in the format of public_suffix_list.dat. Rules with internationalized labels are converted
+// to A-labels. If a rule cannot be converted an error is returned.
+func ParseList(r io.Reader) (List, error) {
+	l := &list{
+		rules: map[string]rule{},
+	}
+	icann := false
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, "// ===BEGIN ICANN DOMAINS==="):
+			icann = true
+			continue
+		case strings.HasPrefix(line, "// ===END ICANN DOMAINS==="):
+			icann = false
+			continue
+		case line == "" || strings.HasPrefix(line, "//"):
+			continue
+		}
+		// Only the text up to the first whitespace is part of the rule.
+		if i := strings.IndexAny(line, " \t"); i >= 0 {
+			line = line[:i]
+		}
+		kind := ruleNormal
+		switch {
+		case strings.HasPrefix(line, "!"):
+			kind = ruleException
+			line = line[1:]
+		case strings.HasPrefix(line, "*."):
+			kind = ruleWildcard
+			line = line[2:]
+		}
+		name, err := toASCII(line)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rule %s in line %d (%w)", line, lineNumber, err)
+		}
+		existing := l.rules[name]
+		existing.kinds |= kind
+		existing.icann = existing.icann || icann
+		l.rules[name] = existing
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read public suffix list (%w)", err)
+	}
+	return l, nil
+}
+
+// PublicSuffix returns the public suffix of the host using the embedded list. See List.PublicSuffix for details.
+func PublicSuffix(host string) (suffix string, icann bool) {
+	return Default().PublicSuffix(host)
+}
+
+// RegistrableDomain returns the registrable domain of the host using the embedded list. See List.RegistrableDomain
+// for details.
+func RegistrableDomain(host string) (string, error) {
+	return Default().RegistrableDomain(host)
+}
+
+// IsPublicSuffix returns true if the host is itself a public suffix according to the embedded list, for example
+// "co.uk". Unlisted top level domains are public suffixes as well.
+func IsPublicSuffix(host string) bool {
+	suffix, _ := PublicSuffix(host)
+	return suffix != "" && suffix == normalizeHost(host)
+}
+
+// IsListedPublicSuffix returns true if the host is itself a public suffix because of an explicit rule in the
+// embedded list. Unlike IsPublicSuffix, it returns false for unlisted single-label hosts such as "localhost".
+func IsListedPublicSuffix(host string) bool {
+	if !IsPublicSuffix(host) {
+		return false
+	}
+	host = normalizeHost(host)
+	// The implicit default rule only ever matches a single label, so longer suffixes are always listed.
+	if strings.Contains(host, ".") {
+		return true
+	}
+	l, ok := Default().(*list)
+	return ok && l.rules[host].kinds&ruleNormal != 0
+}
+
+//endregion
+
+//region Implementation
+
+type ruleKind uint8
+
+const (
+	ruleNormal ruleKind = 1 << iota
+	ruleWildcard
+	ruleException
+)
+
+// rule holds all rules for a name. A name can be listed both as a normal rule and as the base of a wildcard rule.
+type rule struct {
+	kinds ruleKind
+	icann bool
+}
+
+type list struct {
+	rules map[string]rule
+}
+
+var idnaProfile = idna.New(idna.MapForLookup(), idna.StrictDomainName(false), idna.VerifyDNSLength(false))
+
+func toASCII(name string) (string, error) {
+	for i := 0; i < len(name); i++ {
+		if name[i] >= 0x80 {
+			return idnaProfile.ToASCII(name)
+		}
+	}
+	return strings.ToLower(name), nil
+}
+
+// normalizeHost converts the host to lowercase A-labels and removes a trailing dot. Hosts that cannot be converted
+// are only lowercased.
+func normalizeHost(host string) string {
+	host = strings.TrimSuffix(host, ".")
+	if ascii, err := toASCII(host); err == nil {
+		return ascii
+	}
+	return strings.ToLower(host)
+}
+
+func isIPAddress(host string) bool {
+	if strings.HasPrefix(host, "[") {
+		return true
+	}
+	_, err := netip.ParseAddr(host)
+	return err == nil
+}
+
+// suffixLabels returns the number of labels of the public suffix of the normalized host.
+func (l list) suffixLabels(labels []string) (int, bool) {
+	// Exception rules take precedence over all other rules. The public suffix is the exception minus its first label.
+	for i := range labels {
+		if r, ok := l.rules[strings.Join(labels[i:], ".")]; ok && r.kinds&ruleException != 0 {
+			return len(labels) - i - 1, r.icann
+		}
+	}
+	// Otherwise the longest matching rule wins, so the candidates are checked from longest to shortest.
+	for i := range labels {
+		if r, ok := l.rules[strings.Join(labels[i:], ".")]; ok && r.kinds&ruleNormal != 0 {
+			return len(labels) - i, r.icann
+		}
+		if i+1 < len(labels) {
+			if r, ok := l.rules[strings.Join(labels[i+1:], ".")]; ok && r.kinds&ruleWildcard != 0 {
+				return len(labels) - i, r.icann
+			}
+		}
+	}
+	// The implicit default rule "*" makes every unlisted top level domain a public suffix.
+	return 1, false
+}
+
+func (l list) PublicSuffix(host string) (string, bool) {
+	host = normalizeHost(host)
+	if host == "" || isIPAddress(host) {
+		return "", false
+	}
+	labels := strings.Split(host, ".")
+	n, icann := l.suffixLabels(labels)
+	return strings.Join(labels[len(labels)-n:], "."), icann
+}
+
+// RegistrableDomain returns the result in the same form as the input: lowercase, and with U-labels if the host was
+// provided with U-labels.
+func (l list) RegistrableDomain(host string) (string, error) {
+	original := strings.ToLower(strings.TrimSuffix(host, "."))
+	normalized := normalizeHost(host)
+	if normalized == "" {
+		return "", errors.New("empty host")
+	}
+	if isIPAddress(normalized) {
+		return "", fmt.Errorf("%s is an IP address and has no registrable domain", host)
+	}
+	labels := strings.Split(normalized, ".")
+	for _, label := range labels {
+		if label == "" {
+			return "", fmt.Errorf("%s contains an empty label", host)
+		}
+	}
+	n, _ := l.suffixLabels(labels)
+	if n >= len(labels) {
+		return "", fmt.Errorf("%s is a public suffix", host)
+	}
+	if originalLabels := strings.Split(original, "."); len(originalLabels) == len(labels) {
+		labels = originalLabels
+	}
+	return strings.Join(labels[len(labels)-n-1:], "."), nil
+}
+
+//endregion
@@ -0,0 +1,172 @@
+package publicsuffix_test
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"go.debugged.it/gsr7/publicsuffix"
+)
+
+//region Examples
+
+func ExampleRegistrableDomain() {
+	domain, _ := publicsuffix.RegistrableDomain("www.example.co.uk")
+	fmt.Println(domain)
+	_, err := publicsuffix.RegistrableDomain("co.uk")
+	fmt.Println(err)
+	// Output: example.co.uk
+	// co.uk is a public suffix
+}
+
+func ExamplePublicSuffix() {
+	fmt.Println(publicsuffix.PublicSuffix("www.example.co.uk"))
+	fmt.Println(publicsuffix.PublicSuffix("octocat.github.io"))
+	// Output: co.uk true
+	// github.io false
+}
+
+//endregion
+
+//region Tests
+
+// checkPublicSuffixLine matches a test case in the test_psl.txt file of the Public Suffix List project.
+var checkPublicSuffixLine = regexp.MustCompile(`^checkPublicSuffix\((null|'[^']*'), (null|'[^']*')\);`)
+
+func TestRegistrableDomain(t *testing.T) {
+	f, err := os.Open(filepath.Join("testdata", "test_psl.txt"))
+	if err != nil {
+		t.Fatalf("failed to open test_psl.txt (%v)", err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	scanner := bufio.NewScanner(f)
+	tested := 0
+	for scanner.Scan() {
+		match := checkPublicSuffixLine.FindStringSubmatch(scanner.Text())
+		if match == nil || match[1] == "null" {
+			continue
+		}
+		host := strings.Trim(match[1], "'")
+		expected := strings.Trim(match[2], "'")
+		tested++
+		t.Run(
+			host, func(t *testing.T) {
+				domain, err := publicsuffix.RegistrableDomain(host)
+				if expected == "null" {
+					if err == nil {
+						t.Fatalf("no error returned for %s (result: %s)", host, domain)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("failed to determine registrable domain of %s (%v)", host, err)
+				}
+				if domain != expected {
+					t.Fatalf("incorrect registrable domain for %s: %s (expected: %s)", host, domain, expected)
+				}
+			},
+		)
+	}
+	if tested == 0 {
+		t.Fatalf("no test cases found in test_psl.txt")
+	}
+}
+
+func TestPublicSuffix(t *testing.T) {
+	testData := []struct {
+		host   string
+		suffix string
+		icann  bool
+	}{
+		{"example.com", "com", true},
+		{"www.example.co.uk", "co.uk", true},
+		{"EXAMPLE.CO.UK.", "co.uk", true},
+		{"octocat.github.io", "github.io", false},
+		{"example.unlisted-tld", "unlisted-tld", false},
+		{"a.b.c.kobe.jp", "c.kobe.jp", true},
+		{"www.city.kobe.jp", "kobe.jp", true},
+		{"食狮.公司.cn", "xn--55qx5d.cn", true},
+		{"192.0.2.1", "", false},
+		{"[2001:db8::1]", "", false},
+		{"", "", false},
+	}
+	for _, testCase := range testData {
+		t.Run(
+			testCase.host, func(t *testing.T) {
+				suffix, icann := publicsuffix.PublicSuffix(testCase.host)
+				if suffix != testCase.suffix || icann != testCase.icann {
+					t.Fatalf(
+						"incorrect public suffix for %s: %s %t (expected: %s %t)",
+						testCase.host, suffix, icann, testCase.suffix, testCase.icann,
+					)
+				}
+			},
+		)
+	}
+}
+
+func TestIsPublicSuffix(t *testing.T) {
+	for host, expected := range map[string]bool{
+		"com":         true,
+		".com":        false,
+		"co.uk":       true,
+		"example.com": false,
+		"github.io":   true,
+		"192.0.2.1":   false,
+	} {
+		if publicsuffix.IsPublicSuffix(host) != expected {
+			t.Fatalf("incorrect result for %s (expected: %t)", host, expected)
+		}
+	}
+}
+
+func TestIsListedPublicSuffix(t *testing.T) {
+	for host, expected := range map[string]bool{
+		"com":         true,
+		"co.uk":       true,
+		"github.io":   true,
+		"localhost":   false,
+		"intranet":    false,
+		"example.com": false,
+	} {
+		if publicsuffix.IsListedPublicSuffix(host) != expected {
+			t.Fatalf("incorrect result for %s (expected: %t)", host, expected)
+		}
+	}
+}
+
+func TestParseList(t *testing.T) {
+	list, err := publicsuffix.ParseList(
+		strings.NewReader(
+			"// ===BEGIN ICANN DOMAINS===\nexample\n*.wild.example\n!keep.wild.example\n// ===END ICANN DOMAINS===\n",
+		),
+	)
+	if err != nil {
+		t.Fatalf("failed to parse list (%v)", err)
+	}
+	for host, expected := range map[string]string{
+		"a.example":           "a.example",
+		"b.a.wild.example":    "b.a.wild.example",
+		"b.keep.wild.example": "keep.wild.example",
+		"a.com":               "a.com",
+	} {
+		domain, err := list.RegistrableDomain(host)
+		if err != nil {
+			t.Fatalf("failed to determine registrable domain of %s (%v)", host, err)
+		}
+		if domain != expected {
+			t.Fatalf("incorrect registrable domain for %s: %s (expected: %s)", host, domain, expected)
+		}
+	}
+	if _, err := list.RegistrableDomain("a.wild.example"); err == nil {
+		t.Fatalf("no error returned for a wildcard public suffix")
+	}
+}
+
+//endregion
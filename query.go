@@ -0,0 +1,392 @@
+package gsr7
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+//region Constants
+
+// QueryEncoding describes how spaces and plus signs are represented in a query string.
+type QueryEncoding uint8
+
+const (
+	// QueryEncodingRFC3986 encodes spaces as %20 and plus signs as %2B. When decoding, a plus sign is kept as it is.
+	QueryEncodingRFC3986 QueryEncoding = iota
+	// QueryEncodingForm encodes spaces as + and plus signs as %2B as described for the
+	// application/x-www-form-urlencoded format. When decoding, a plus sign is converted to a space.
+	QueryEncodingForm
+)
+
+//endregion
+
+//region Interface
+
+// QueryParameter is a single name-value pair in a query string.
+type QueryParameter struct {
+	Name  string
+	Value string
+}
+
+// Query is an immutable, ordered list of query parameters. Parameter names may be repeated, and the order of the
+// parameters is kept when the query is encoded again. Parameters that were parsed and not changed are rendered in
+// their original encoding by String, so modifying a single parameter does not change the rest of the query string.
+type Query interface {
+	// String encodes the query string in the RFC 3986 style. Unchanged parsed parameters keep their original
+	// encoding.
+	String() string
+	// Encode encodes all parameters using the specified encoding, ignoring the original encoding of parsed parameters.
+	Encode(encoding QueryEncoding) string
+
+	// Len returns the number of parameters, including repeated names.
+	Len() int
+	// GetParameters returns a copy of all decoded parameters in order.
+	GetParameters() []QueryParameter
+	// GetNames returns the distinct parameter names in the order of their first occurrence.
+	GetNames() []string
+	// HasParameter returns true if at least one parameter with the specified name exists.
+	HasParameter(name string) bool
+	// GetParameter returns all values for the specified parameter name in order.
+	GetParameter(name string) []string
+	// GetParameterValue returns the first value for the specified parameter name, or an empty string if the parameter
+	// is not present.
+	GetParameterValue(name string) string
+
+	// WithParameter returns a copy of the query with all values of the specified parameter replaced by a single value.
+	// The new value takes the position of the first existing occurrence, or is appended if the parameter is new.
+	WithParameter(name, value string) Query
+	// WithParameterValues returns a copy of the query with all values of the specified parameter replaced. The new
+	// values take the position of the first existing occurrence, or are appended if the parameter is new.
+	WithParameterValues(name string, values []string) Query
+	// WithAddedParameter returns a copy of the query with the specified parameter appended, keeping existing values.
+	WithAddedParameter(name, value string) Query
+	// WithoutParameter returns a copy of the query with all occurrences of the specified parameter removed.
+	WithoutParameter(name string) Query
+
+	// GetNested interprets the parameter names using the bracket notation known from PHP and returns the resulting
+	// structure. For example, a[]=1&a[]=2&b[c]=3 results in {"a": ["1", "2"], "b": {"c": "3"}}. The values in the
+	// returned map are either strings, []any for consecutively numbered entries, or map[string]any. As in PHP, a later
+	// parameter overwrites an earlier one with the same name.
+	GetNested() map[string]any
+}
+
+// NewQuery creates an empty query. Parameters can be added using the With* methods.
+func NewQuery() Query {
+	return &query{}
+}
+
+// ParseQuery parses a query string without the leading question mark. If the query string contains invalid
+// percent-encoding a panic is thrown.
+func ParseQuery(queryString string, encoding QueryEncoding) Query {
+	return Must(ParseQueryE(queryString, encoding))
+}
+
+// ParseQueryE parses a query string without the leading question mark. Parameters are separated by an ampersand, a
+// parameter without an equals sign has an empty value. Empty segments, as in "a=1&&b=2", are not parameters, but are
+// kept so String returns the original query string. If the query string contains invalid percent-encoding an error
+// is returned.
+func ParseQueryE(queryString string, encoding QueryEncoding) (Query, error) {
+	q := &query{}
+	if queryString == "" {
+		return q, nil
+	}
+	emptySegments := 0
+	for _, part := range strings.Split(queryString, "&") {
+		if part == "" {
+			emptySegments++
+			continue
+		}
+		name, value := part, ""
+		if i := strings.IndexByte(part, '='); i >= 0 {
+			name, value = part[:i], part[i+1:]
+		}
+		if err := validate(
+			validateURIComponent("query parameter name", name, isAnyChar),
+			validateURIComponent("query parameter value", value, isAnyChar),
+		); err != nil {
+			return nil, fmt.Errorf("failed to parse query string %s (%w)", queryString, err)
+		}
+		q.parameters = append(
+			q.parameters, queryParameter{
+				QueryParameter: QueryParameter{
+					Name:  decodeQueryComponent(name, encoding),
+					Value: decodeQueryComponent(value, encoding),
+				},
+				raw:           part,
+				emptySegments: emptySegments,
+			},
+		)
+		emptySegments = 0
+	}
+	q.emptySegments = emptySegments
+	return q, nil
+}
+
+//endregion
+
+//region Implementation
+
+type queryParameter struct {
+	QueryParameter
+	// raw holds the original encoded form of a parsed parameter. It is empty for parameters added later.
+	raw string
+	// emptySegments is the number of empty segments before a parsed parameter.
+	emptySegments int
+}
+
+type query struct {
+	parameters []queryParameter
+	// emptySegments is the number of empty segments after the last parameter of a parsed query.
+	emptySegments int
+}
+
+func decodeQueryComponent(s string, encoding QueryEncoding) string {
+	if encoding == QueryEncodingForm {
+		s = strings.ReplaceAll(s, "+", " ")
+	}
+	return percentDecode(s)
+}
+
+func isQueryParameterChar(c byte) bool {
+	return isQueryChar(c) && c != '&' && c != '=' && c != '+'
+}
+
+func encodeQueryComponent(s string, encoding QueryEncoding) string {
+	encoded := percentEncodeAll(s, isQueryParameterChar)
+	if encoding == QueryEncodingForm {
+		encoded = strings.ReplaceAll(encoded, "%20", "+")
+	}
+	return encoded
+}
+
+func (p queryParameter) encode(encoding QueryEncoding) string {
+	return encodeQueryComponent(p.Name, encoding) + "=" + encodeQueryComponent(p.Value, encoding)
+}
+
+func (q query) String() string {
+	parts := make([]string, 0, len(q.parameters)+q.emptySegments)
+	for _, p := range q.parameters {
+		if p.raw != "" {
+			parts = append(parts, make([]string, p.emptySegments)...)
+			parts = append(parts, p.raw)
+		} else {
+			parts = append(parts, p.encode(QueryEncodingRFC3986))
+		}
+	}
+	return strings.Join(append(parts, make([]string, q.emptySegments)...), "&")
+}
+
+func (q query) Encode(encoding QueryEncoding) string {
+	parts := make([]string, len(q.parameters))
+	for i, p := range q.parameters {
+		parts[i] = p.encode(encoding)
+	}
+	return strings.Join(parts, "&")
+}
+
+func (q query) Len() int {
+	return len(q.parameters)
+}
+
+func (q query) GetParameters() []QueryParameter {
+	result := make([]QueryParameter, len(q.parameters))
+	for i, p := range q.parameters {
+		result[i] = p.QueryParameter
+	}
+	return result
+}
+
+func (q query) GetNames() []string {
+	var names []string
+	seen := map[string]struct{}{}
+	for _, p := range q.parameters {
+		if _, ok := seen[p.Name]; !ok {
+			seen[p.Name] = struct{}{}
+			names = append(names, p.Name)
+		}
+	}
+	return names
+}
+
+func (q query) HasParameter(name string) bool {
+	for _, p := range q.parameters {
+		if p.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (q query) GetParameter(name string) []string {
+	var values []string
+	for _, p := range q.parameters {
+		if p.Name == name {
+			values = append(values, p.Value)
+		}
+	}
+	return values
+}
+
+func (q query) GetParameterValue(name string) string {
+	for _, p := range q.parameters {
+		if p.Name == name {
+			return p.Value
+		}
+	}
+	return ""
+}
+
+func (q query) WithParameter(name, value string) Query {
+	return q.WithParameterValues(name, []string{value})
+}
+
+func (q query) WithParameterValues(name string, values []string) Query {
+	newParameters := make([]queryParameter, 0, len(q.parameters)+len(values))
+	inserted := false
+	for _, p := range q.parameters {
+		if p.Name != name {
+			newParameters = append(newParameters, p)
+			continue
+		}
+		if !inserted {
+			for _, value := range values {
+				newParameters = append(newParameters, queryParameter{QueryParameter: QueryParameter{name, value}})
+			}
+			inserted = true
+		}
+	}
+	if !inserted {
+		for _, value := range values {
+			newParameters = append(newParameters, queryParameter{QueryParameter: QueryParameter{name, value}})
+		}
+	}
+	return &query{
+		parameters: newParameters,
+	}
+}
+
+func (q query) WithAddedParameter(name, value string) Query {
+	newParameters := make([]queryParameter, len(q.parameters), len(q.parameters)+1)
+	copy(newParameters, q.parameters)
+	return &query{
+		parameters: append(newParameters, queryParameter{QueryParameter: QueryParameter{name, value}}),
+	}
+}
+
+func (q query) WithoutParameter(name string) Query {
+	newParameters := make([]queryParameter, 0, len(q.parameters))
+	for _, p := range q.parameters {
+		if p.Name != name {
+			newParameters = append(newParameters, p)
+		}
+	}
+	return &query{
+		parameters: newParameters,
+	}
+}
+
+// queryNode is an ordered map used while building the nested structure for GetNested. Like PHP arrays it tracks the
+// next free integer key for the [] notation.
+type queryNode struct {
+	keys   []string
+	values map[string]any
+	next   int
+}
+
+func newQueryNode() *queryNode {
+	return &queryNode{values: map[string]any{}}
+}
+
+func (n *queryNode) set(key string, value any) {
+	if key == "" {
+		key = strconv.Itoa(n.next)
+	}
+	if index, err := strconv.Atoi(key); err == nil && index >= n.next && strconv.Itoa(index) == key {
+		n.next = index + 1
+	}
+	if _, ok := n.values[key]; !ok {
+		n.keys = append(n.keys, key)
+	}
+	n.values[key] = value
+}
+
+func (n *queryNode) child(key string) *queryNode {
+	if key != "" {
+		if existing, ok := n.values[key].(*queryNode); ok {
+			return existing
+		}
+	}
+	child := newQueryNode()
+	n.set(key, child)
+	return child
+}
+
+func (n *queryNode) export() any {
+	isList := true
+	for i, key := range n.keys {
+		if key != strconv.Itoa(i) {
+			isList = false
+			break
+		}
+	}
+	if isList && len(n.keys) > 0 {
+		list := make([]any, len(n.keys))
+		for i, key := range n.keys {
+			list[i] = exportQueryValue(n.values[key])
+		}
+		return list
+	}
+	result := make(map[string]any, len(n.keys))
+	for _, key := range n.keys {
+		result[key] = exportQueryValue(n.values[key])
+	}
+	return result
+}
+
+func exportQueryValue(value any) any {
+	if node, ok := value.(*queryNode); ok {
+		return node.export()
+	}
+	return value
+}
+
+// splitQueryName splits a parameter name in the a[b][c] notation into its keys. Names with unbalanced brackets are
+// returned as a single key.
+func splitQueryName(name string) []string {
+	open := strings.IndexByte(name, '[')
+	if open <= 0 {
+		return []string{name}
+	}
+	keys := []string{name[:open]}
+	rest := name[open:]
+	for rest != "" && rest[0] == '[' {
+		closing := strings.IndexByte(rest, ']')
+		if closing < 0 {
+			return []string{name}
+		}
+		keys = append(keys, rest[1:closing])
+		rest = rest[closing+1:]
+	}
+	// As in PHP, anything after the last closing bracket is ignored.
+	return keys
+}
+
+func (q query) GetNested() map[string]any {
+	root := newQueryNode()
+	for _, p := range q.parameters {
+		keys := splitQueryName(p.Name)
+		node := root
+		for _, key := range keys[:len(keys)-1] {
+			node = node.child(key)
+		}
+		node.set(keys[len(keys)-1], p.Value)
+	}
+	result := make(map[string]any, len(root.keys))
+	for _, key := range root.keys {
+		result[key] = exportQueryValue(root.values[key])
+	}
+	return result
+}
+
+//endregion
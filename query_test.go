@@ -0,0 +1,127 @@
+package gsr7_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"go.debugged.it/gsr7"
+)
+
+//region Examples
+
+func ExampleParseQuery() {
+	query := gsr7.ParseQuery("a=1&b=2&a=3", gsr7.QueryEncodingRFC3986)
+	fmt.Println(query.GetParameter("a"))
+	fmt.Println(query.GetParameterValue("b"))
+	// Output: [1 3]
+	// 2
+}
+
+func ExampleQuery_Encode() {
+	query := gsr7.NewQuery().
+		WithAddedParameter("q", "hello world").
+		WithAddedParameter("lang", "c++")
+	fmt.Println(query.Encode(gsr7.QueryEncodingRFC3986))
+	fmt.Println(query.Encode(gsr7.QueryEncodingForm))
+	// Output: q=hello%20world&lang=c%2B%2B
+	// q=hello+world&lang=c%2B%2B
+}
+
+func ExampleURI_WithQueryValues() {
+	uri := gsr7.ParseURI("https://example.com/search?q=go%20lang&page=1&sort=asc")
+	uri = uri.WithQueryValues(uri.GetQueryValues().WithParameter("page", "2").WithoutParameter("sort"))
+	fmt.Println(uri)
+	// Output: https://example.com/search?q=go%20lang&page=2
+}
+
+//endregion
+
+//region Tests
+
+func TestParseQuery(t *testing.T) {
+	query := gsr7.ParseQuery("a=1&b&c=&a=x+y&d=%26%3D", gsr7.QueryEncodingForm)
+	expected := []gsr7.QueryParameter{
+		{"a", "1"},
+		{"b", ""},
+		{"c", ""},
+		{"a", "x y"},
+		{"d", "&="},
+	}
+	if !reflect.DeepEqual(query.GetParameters(), expected) {
+		t.Fatalf("incorrect parameters: %v", query.GetParameters())
+	}
+	assertEquals(t, query.Len(), 5, "incorrect number of parameters: %d", query.Len())
+	if !reflect.DeepEqual(query.GetNames(), []string{"a", "b", "c", "d"}) {
+		t.Fatalf("incorrect names: %v", query.GetNames())
+	}
+	assertEquals(t, query.String(), "a=1&b&c=&a=x+y&d=%26%3D", "incorrect query string: %s", query)
+
+	rfc := gsr7.ParseQuery("a=x+y", gsr7.QueryEncodingRFC3986)
+	assertEquals(t, rfc.GetParameterValue("a"), "x+y", "incorrect value: %s", rfc.GetParameterValue("a"))
+
+	for _, input := range []string{"a=1&&b=2", "&a=1", "a=1&", "&", "&&", "a&&&b&"} {
+		empty := gsr7.ParseQuery(input, gsr7.QueryEncodingRFC3986)
+		assertEquals(t, empty.String(), input, "empty segments not preserved: %s", empty)
+	}
+	empty := gsr7.ParseQuery("a=1&&b=2&", gsr7.QueryEncodingRFC3986)
+	assertEquals(t, empty.Len(), 2, "empty segments counted as parameters")
+	assertEquals(t, empty.Encode(gsr7.QueryEncodingRFC3986), "a=1&b=2", "empty segments encoded")
+	assertEquals(t, empty.WithParameter("b", "3").String(), "a=1&b=3", "incorrect modified query")
+
+	if _, err := gsr7.ParseQueryE("a=%zz", gsr7.QueryEncodingRFC3986); err == nil {
+		t.Fatalf("invalid percent-encoding did not result in an error")
+	}
+}
+
+func TestQueryWithMethods(t *testing.T) {
+	query := gsr7.ParseQuery("a=1&b=2&a=3&c=4", gsr7.QueryEncodingRFC3986)
+
+	replaced := query.WithParameter("a", "5")
+	assertEquals(t, replaced.String(), "a=5&b=2&c=4", "incorrect query: %s", replaced)
+	assertEquals(t, query.String(), "a=1&b=2&a=3&c=4", "the original query was modified: %s", query)
+
+	values := query.WithParameterValues("b", []string{"x", "y"})
+	assertEquals(t, values.String(), "a=1&b=x&b=y&a=3&c=4", "incorrect query: %s", values)
+
+	added := query.WithAddedParameter("a", "a b&c")
+	assertEquals(t, added.String(), "a=1&b=2&a=3&c=4&a=a%20b%26c", "incorrect query: %s", added)
+
+	removed := query.WithoutParameter("a")
+	assertEquals(t, removed.String(), "b=2&c=4", "incorrect query: %s", removed)
+	assertEquals(t, removed.HasParameter("a"), false, "parameter was not removed")
+}
+
+func TestQueryGetNested(t *testing.T) {
+	query := gsr7.ParseQuery(
+		"a[]=1&a[]=2&b[c]=3&b[d][]=4&e=5&f[x]=6&f[]=7&g[h=8",
+		gsr7.QueryEncodingForm,
+	)
+	expected := map[string]any{
+		"a": []any{"1", "2"},
+		"b": map[string]any{
+			"c": "3",
+			"d": []any{"4"},
+		},
+		"e": "5",
+		"f": map[string]any{
+			"x": "6",
+			"0": "7",
+		},
+		"g[h": "8",
+	}
+	if result := query.GetNested(); !reflect.DeepEqual(result, expected) {
+		t.Fatalf("incorrect nested structure: %v", result)
+	}
+}
+
+func TestURIQueryValues(t *testing.T) {
+	uri := gsr7.ParseURI("http://example.com/?a=%41&b=1")
+	withAdded := uri.WithQueryValues(uri.GetQueryValues().WithAddedParameter("c", "/?"))
+	assertEquals(t, withAdded.String(), "http://example.com/?a=%41&b=1&c=/?", "incorrect URI: %s", withAdded)
+
+	empty := uri.WithQueryValues(gsr7.NewQuery())
+	assertEquals(t, empty.String(), "http://example.com/", "query was not removed: %s", empty)
+}
+
+//endregion
@@ -0,0 +1,296 @@
+package gsr7
+
+import (
+	"fmt"
+	"strings"
+)
+
+//region Constants
+
+// RequestTargetForm describes the form of the request target in the request line.
+//
+// See https://datatracker.ietf.org/doc/html/rfc9112#section-3.2 for details.
+type RequestTargetForm uint8
+
+const (
+	// RequestTargetFormOrigin is the path and query of the target URI, such as "/index.html?page=1". It is used for
+	// requests sent directly to the origin server.
+	RequestTargetFormOrigin RequestTargetForm = iota
+	// RequestTargetFormAbsolute is the complete target URI without a fragment, such as
+	// "http://example.com/index.html". It is used for requests sent to a proxy.
+	RequestTargetFormAbsolute
+	// RequestTargetFormAuthority is the host and port of the target, such as "example.com:443". It is only used for
+	// CONNECT requests.
+	RequestTargetFormAuthority
+	// RequestTargetFormAsterisk is a single "*". It is only used for OPTIONS requests that apply to the server as a
+	// whole rather than to a specific resource.
+	RequestTargetFormAsterisk
+)
+
+// String returns the name of the form as used in RFC 9112.
+func (f RequestTargetForm) String() string {
+	switch f {
+	case RequestTargetFormOrigin:
+		return "origin-form"
+	case RequestTargetFormAbsolute:
+		return "absolute-form"
+	case RequestTargetFormAuthority:
+		return "authority-form"
+	case RequestTargetFormAsterisk:
+		return "asterisk-form"
+	default:
+		return "unknown"
+	}
+}
+
+//endregion
+
+//region Interface
+
+// RequestTarget is the parsed request target from the request line of a HTTP/1.x request.
+//
+// See https://datatracker.ietf.org/doc/html/rfc9112#section-3.2 for details.
+type RequestTarget interface {
+	// String returns the request target as it appears in the request line.
+	String() string
+	// GetForm returns the form of the request target.
+	GetForm() RequestTargetForm
+	// GetURI returns the URI reference contained in the request target: a relative reference with the path and query
+	// for origin-form, the absolute URI for absolute-form, a reference with only an authority for authority-form and
+	// an empty reference for asterisk-form.
+	GetURI() URI
+	// ResolveURI reconstructs the target URI. For absolute-form the URI of the request target is returned. For the
+	// other forms, the scheme and the authority are taken from the base, which is typically built from the
+	// connection and the Host header, and the authority-form replaces the authority. If the result has no authority,
+	// a path starting with "//" is prefixed with "/." so it cannot be mistaken for an authority.
+	//
+	// See https://datatracker.ietf.org/doc/html/rfc9112#section-3.3 for details.
+	ResolveURI(base URI) URI
+}
+
+// ParseRequestTarget parses the request target of a request with the specified method. If the request target is
+// invalid a panic is thrown.
+func ParseRequestTarget(method string, target string) RequestTarget {
+	return Must(ParseRequestTargetE(method, target))
+}
+
+// ParseRequestTargetE parses the request target of a request with the specified method. The form is determined from
+// the method, since "example.com:443" is a valid absolute URI as well as an authority: CONNECT requests must use
+// the authority-form with an explicit port, the asterisk-form is only accepted for OPTIONS requests, targets starting
+// with a slash are in origin-form and all others must be absolute URIs. An error is returned if the request target
+// does not match its form, contains a fragment or user info, or contains whitespace or control characters. Targets in
+// origin-form must not start with "//", since they would be read as an authority when the request has no Host.
+func ParseRequestTargetE(method string, target string) (RequestTarget, error) {
+	if err := validate(validateRequestTarget(target)); err != nil {
+		return nil, err
+	}
+	if method == "CONNECT" {
+		return parseAuthorityForm(target)
+	}
+	switch {
+	case target == "*":
+		if method != "OPTIONS" {
+			return nil, fmt.Errorf("the asterisk-form request target is only allowed for OPTIONS requests")
+		}
+		return &requestTarget{form: RequestTargetFormAsterisk, uri: &uri{}}, nil
+	case strings.HasPrefix(target, "/"):
+		return parseOriginForm(target)
+	default:
+		return parseAbsoluteForm(target)
+	}
+}
+
+// NewRequestTarget creates the request target for a request with the specified method to the specified URI. If the
+// URI cannot be expressed in the required form a panic is thrown.
+func NewRequestTarget(method string, targetURI URI, viaProxy bool) RequestTarget {
+	return Must(NewRequestTargetE(method, targetURI, viaProxy))
+}
+
+// NewRequestTargetE creates the request target for a request with the specified method to the specified URI. The
+// form is chosen as follows:
+//
+//   - CONNECT requests use the authority-form. If the URI has no port, the default port of its scheme is used.
+//   - Requests sent via a proxy use the absolute-form without user info and fragment.
+//   - OPTIONS requests to a URI without path and query use the asterisk-form.
+//   - All other requests use the origin-form, with "/" as the path if the path of the URI is empty.
+//
+// An error is returned if the URI has no host for the authority-form, or no scheme and host for the absolute-form.
+func NewRequestTargetE(method string, targetURI URI, viaProxy bool) (RequestTarget, error) {
+	if targetURI == nil {
+		return nil, fmt.Errorf("no URI for the request target")
+	}
+	u, err := toURI(targetURI)
+	if err != nil {
+		return nil, err
+	}
+	target := *u
+	target.userInfo = ""
+	target.emptyUserInfo = false
+	target.hasFragment = false
+	target.fragment = ""
+	switch {
+	case method == "CONNECT":
+		if target.host == "" {
+			return nil, fmt.Errorf("the URI %s has no host for the authority-form request target", targetURI)
+		}
+		port := target.port
+		if port == nil {
+			defaultPort, ok := defaultPorts[strings.ToLower(target.scheme)]
+			if !ok {
+				return nil, fmt.Errorf("the URI %s has no port for the authority-form request target", targetURI)
+			}
+			port = &defaultPort
+		}
+		return &requestTarget{
+			form: RequestTargetFormAuthority,
+			uri:  &uri{hasAuthority: true, host: target.host, port: port},
+		}, nil
+	case viaProxy:
+		if target.scheme == "" || target.host == "" {
+			return nil, fmt.Errorf("the URI %s is not absolute and cannot be sent via a proxy", targetURI)
+		}
+		if target.path == "" && method != "OPTIONS" {
+			target.path = "/"
+		}
+		return &requestTarget{form: RequestTargetFormAbsolute, uri: &target}, nil
+	case method == "OPTIONS" && target.path == "" && !target.hasQuery:
+		return &requestTarget{form: RequestTargetFormAsterisk, uri: &uri{}}, nil
+	default:
+		return newOriginForm(target.path, target.hasQuery, target.query), nil
+	}
+}
+
+//endregion
+
+//region Implementation
+
+type requestTarget struct {
+	form RequestTargetForm
+	uri  *uri
+}
+
+func newOriginForm(path string, hasQuery bool, query string) *requestTarget {
+	if path == "" {
+		path = "/"
+	}
+	return &requestTarget{
+		form: RequestTargetFormOrigin,
+		uri:  &uri{path: path, hasQuery: hasQuery, query: query},
+	}
+}
+
+func parseOriginForm(target string) (RequestTarget, error) {
+	if strings.IndexByte(target, '#') >= 0 {
+		return nil, fmt.Errorf("the request target %s contains a fragment", target)
+	}
+	path, query, hasQuery := target, "", false
+	if i := strings.IndexByte(target, '?'); i >= 0 {
+		path, query, hasQuery = target[:i], target[i+1:], true
+	}
+	if strings.HasPrefix(path, "//") {
+		return nil, fmt.Errorf("the origin-form request target %s starts with an empty segment", target)
+	}
+	if err := validate(
+		validateURIComponent("path", path, isPathChar),
+		validateURIComponent("query", query, isQueryChar),
+	); err != nil {
+		return nil, fmt.Errorf("invalid origin-form request target %s (%w)", target, err)
+	}
+	return newOriginForm(path, hasQuery, query), nil
+}
+
+func parseAbsoluteForm(target string) (RequestTarget, error) {
+	parsed, err := ParseURIE(target)
+	if err != nil {
+		return nil, fmt.Errorf("invalid absolute-form request target %s (%w)", target, err)
+	}
+	u := parsed.(*uri)
+	switch {
+	case u.scheme == "":
+		return nil, fmt.Errorf("the request target %s is neither in origin-form nor an absolute URI", target)
+	case u.hasFragment:
+		return nil, fmt.Errorf("the request target %s contains a fragment", target)
+	case u.userInfo != "":
+		return nil, fmt.Errorf("the request target %s contains user info", target)
+	}
+	return &requestTarget{form: RequestTargetFormAbsolute, uri: u}, nil
+}
+
+func parseAuthorityForm(target string) (RequestTarget, error) {
+	parsed, err := ParseURIE("//" + target)
+	if err != nil {
+		return nil, fmt.Errorf("invalid authority-form request target %s (%w)", target, err)
+	}
+	u := parsed.(*uri)
+	if u.host == "" || u.port == nil || u.userInfo != "" || u.path != "" || u.hasQuery || u.hasFragment ||
+		strings.IndexByte(target, '@') >= 0 {
+		return nil, fmt.Errorf("the request target %s is not in the host:port authority-form", target)
+	}
+	return &requestTarget{form: RequestTargetFormAuthority, uri: u}, nil
+}
+
+func (t requestTarget) String() string {
+	switch t.form {
+	case RequestTargetFormAuthority:
+		return t.uri.GetAuthority()
+	case RequestTargetFormAsterisk:
+		return "*"
+	default:
+		return t.uri.String()
+	}
+}
+
+func (t requestTarget) GetForm() RequestTargetForm {
+	return t.form
+}
+
+func (t requestTarget) GetURI() URI {
+	u := *t.uri
+	return &u
+}
+
+func (t requestTarget) ResolveURI(base URI) URI {
+	if t.form == RequestTargetFormAbsolute {
+		return t.GetURI()
+	}
+	result := uri{}
+	if base != nil {
+		if b, err := toURI(base); err == nil {
+			result.scheme = b.scheme
+			result.hasAuthority = b.hasAuthority
+			result.userInfo = b.userInfo
+			result.emptyUserInfo = b.emptyUserInfo
+			result.host = b.host
+			result.port = b.port
+			result.rawPort = b.rawPort
+		}
+	}
+	switch t.form {
+	case RequestTargetFormAuthority:
+		result.hasAuthority = true
+		result.userInfo = ""
+		result.emptyUserInfo = false
+		result.host = t.uri.host
+		result.port = t.uri.port
+		result.rawPort = t.uri.rawPort
+	case RequestTargetFormOrigin:
+		result.path = protectEmptyAuthority(t.uri.path, result.hasAuthority)
+		result.hasQuery = t.uri.hasQuery
+		result.query = t.uri.query
+	}
+	return &result
+}
+
+// requestTargetFor returns the request target for a request sent directly to the origin server. If the URI cannot
+// be expressed in the form required by the method, the origin-form is used.
+func requestTargetFor(method string, u URI) RequestTarget {
+	if target, err := NewRequestTargetE(method, u, false); err == nil {
+		return target
+	}
+	if u == nil {
+		return newOriginForm("", false, "")
+	}
+	return newOriginForm(u.GetPath(), u.GetQuery() != "", u.GetQuery())
+}
+
+//endregion
@@ -0,0 +1,283 @@
+// Package status contains the HTTP status code registry. It covers all status codes registered with IANA, including
+// the WebDAV extensions and the codes of RFC 6585, and allows registering vendor-specific codes such as 499.
+//
+// See https://www.iana.org/assignments/http-status-codes/http-status-codes.xhtml for details.
+package status
+
+import (
+	"fmt"
+	"sync"
+)
+
+//region Constants
+
+// Informational 1xx status codes.
+const (
+	Continue           uint16 = 100
+	SwitchingProtocols uint16 = 101
+	Processing         uint16 = 102
+	EarlyHints         uint16 = 103
+)
+
+// Successful 2xx status codes.
+const (
+	OK                          uint16 = 200
+	Created                     uint16 = 201
+	Accepted                    uint16 = 202
+	NonAuthoritativeInformation uint16 = 203
+	NoContent                   uint16 = 204
+	ResetContent                uint16 = 205
+	PartialContent              uint16 = 206
+	MultiStatus                 uint16 = 207
+	AlreadyReported             uint16 = 208
+	IMUsed                      uint16 = 226
+)
+
+// Redirection 3xx status codes.
+const (
+	MultipleChoices   uint16 = 300
+	MovedPermanently  uint16 = 301
+	Found             uint16 = 302
+	SeeOther          uint16 = 303
+	NotModified       uint16 = 304
+	UseProxy          uint16 = 305
+	TemporaryRedirect uint16 = 307
+	PermanentRedirect uint16 = 308
+)
+
+// Client error 4xx status codes.
+const (
+	BadRequest                  uint16 = 400
+	Unauthorized                uint16 = 401
+	PaymentRequired             uint16 = 402
+	Forbidden                   uint16 = 403
+	NotFound                    uint16 = 404
+	MethodNotAllowed            uint16 = 405
+	NotAcceptable               uint16 = 406
+	ProxyAuthenticationRequired uint16 = 407
+	RequestTimeout              uint16 = 408
+	Conflict                    uint16 = 409
+	Gone                        uint16 = 410
+	LengthRequired              uint16 = 411
+	PreconditionFailed          uint16 = 412
+	ContentTooLarge             uint16 = 413
+	URITooLong                  uint16 = 414
+	UnsupportedMediaType        uint16 = 415
+	RangeNotSatisfiable         uint16 = 416
+	ExpectationFailed           uint16 = 417
+	MisdirectedRequest          uint16 = 421
+	UnprocessableContent        uint16 = 422
+	Locked                      uint16 = 423
+	FailedDependency            uint16 = 424
+	TooEarly                    uint16 = 425
+	UpgradeRequired             uint16 = 426
+	PreconditionRequired        uint16 = 428
+	TooManyRequests             uint16 = 429
+	RequestHeaderFieldsTooLarge uint16 = 431
+	UnavailableForLegalReasons  uint16 = 451
+)
+
+// Server error 5xx status codes.
+const (
+	InternalServerError           uint16 = 500
+	NotImplemented                uint16 = 501
+	BadGateway                    uint16 = 502
+	ServiceUnavailable            uint16 = 503
+	GatewayTimeout                uint16 = 504
+	HTTPVersionNotSupported       uint16 = 505
+	VariantAlsoNegotiates         uint16 = 506
+	InsufficientStorage           uint16 = 507
+	LoopDetected                  uint16 = 508
+	NotExtended                   uint16 = 510
+	NetworkAuthenticationRequired uint16 = 511
+)
+
+//endregion
+
+//region Interface
+
+// Status describes a status code.
+type Status struct {
+	// Code is the three-digit status code.
+	Code uint16
+	// ReasonPhrase is the reason phrase sent along with the code in HTTP/1.x.
+	ReasonPhrase string
+	// CacheableByDefault is true if responses with this code can be cached without explicit freshness information.
+	//
+	// See https://datatracker.ietf.org/doc/html/rfc9111#section-4.2.2 for details.
+	CacheableByDefault bool
+	// Retryable is true if the request may succeed when it is repeated later without modification, for example
+	// after a rate limit or a temporary outage.
+	Retryable bool
+}
+
+// Lookup returns the description of a registered status code. If the code is not registered false is returned.
+func Lookup(code uint16) (Status, bool) {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+	s, ok := registry[code]
+	return s, ok
+}
+
+// ReasonPhrase returns the reason phrase of the status code, or an empty string if the code is not registered.
+func ReasonPhrase(code uint16) string {
+	s, _ := Lookup(code)
+	return s.ReasonPhrase
+}
+
+// RegisterStatus adds a vendor-specific status code, such as 499 Client Closed Request, to the registry. A custom code
+// can be registered again to change its description. An error is returned if the code is outside the range 100-599,
+// if it is registered with IANA, or if the reason phrase contains control characters.
+func RegisterStatus(s Status) error {
+	if s.Code < 100 || s.Code > 599 {
+		return fmt.Errorf("invalid status code: %d", s.Code)
+	}
+	for i := 0; i < len(s.ReasonPhrase); i++ {
+		if c := s.ReasonPhrase[i]; (c < ' ' && c != '\t') || c == 0x7f {
+			return fmt.Errorf("invalid character in reason phrase position %d (%d)", i, c)
+		}
+	}
+	if _, ok := ianaRegistry[s.Code]; ok {
+		return fmt.Errorf("the status code %d is registered with IANA and cannot be changed", s.Code)
+	}
+	registryLock.Lock()
+	defer registryLock.Unlock()
+	registry[s.Code] = s
+	return nil
+}
+
+// IsInformational returns true for 1xx status codes, which are interim responses sent before the final response.
+func IsInformational(code uint16) bool {
+	return code >= 100 && code < 200
+}
+
+// IsSuccess returns true for 2xx status codes, which indicate that the request was successfully received, understood
+// and accepted.
+func IsSuccess(code uint16) bool {
+	return code >= 200 && code < 300
+}
+
+// IsRedirect returns true for 3xx status codes, which indicate that further action is needed to complete the
+// request.
+func IsRedirect(code uint16) bool {
+	return code >= 300 && code < 400
+}
+
+// IsClientError returns true for 4xx status codes, which indicate that the request contains an error.
+func IsClientError(code uint16) bool {
+	return code >= 400 && code < 500
+}
+
+// IsServerError returns true for 5xx status codes, which indicate that the server failed to fulfill a valid
+// request.
+func IsServerError(code uint16) bool {
+	return code >= 500 && code < 600
+}
+
+// IsCacheableByDefault returns true if responses with the status code are heuristically cacheable, which means they
+// can be cached without explicit freshness information. Unregistered codes are not cacheable by default.
+//
+// See https://datatracker.ietf.org/doc/html/rfc9110#section-15.1 for details.
+func IsCacheableByDefault(code uint16) bool {
+	s, _ := Lookup(code)
+	return s.CacheableByDefault
+}
+
+// IsRetryable returns true if a request that received the status code may succeed when it is repeated later without
+// modification. This covers timeouts, rate limits and temporary unavailability of the server or an upstream server.
+// A client must still only retry requests with idempotent methods automatically.
+func IsRetryable(code uint16) bool {
+	s, _ := Lookup(code)
+	return s.Retryable
+}
+
+//endregion
+
+//region Implementation
+
+// ianaStatuses contains the status codes registered with IANA. The codes 306 and 418 are reserved as "(Unused)" and
+// therefore not listed.
+var ianaStatuses = []Status{
+	{Code: Continue, ReasonPhrase: "Continue"},
+	{Code: SwitchingProtocols, ReasonPhrase: "Switching Protocols"},
+	{Code: Processing, ReasonPhrase: "Processing"},
+	{Code: EarlyHints, ReasonPhrase: "Early Hints"},
+
+	{Code: OK, ReasonPhrase: "OK", CacheableByDefault: true},
+	{Code: Created, ReasonPhrase: "Created"},
+	{Code: Accepted, ReasonPhrase: "Accepted"},
+	{Code: NonAuthoritativeInformation, ReasonPhrase: "Non-Authoritative Information", CacheableByDefault: true},
+	{Code: NoContent, ReasonPhrase: "No Content", CacheableByDefault: true},
+	{Code: ResetContent, ReasonPhrase: "Reset Content"},
+	{Code: PartialContent, ReasonPhrase: "Partial Content", CacheableByDefault: true},
+	{Code: MultiStatus, ReasonPhrase: "Multi-Status"},
+	{Code: AlreadyReported, ReasonPhrase: "Already Reported"},
+	{Code: IMUsed, ReasonPhrase: "IM Used"},
+
+	{Code: MultipleChoices, ReasonPhrase: "Multiple Choices", CacheableByDefault: true},
+	{Code: MovedPermanently, ReasonPhrase: "Moved Permanently", CacheableByDefault: true},
+	{Code: Found, ReasonPhrase: "Found"},
+	{Code: SeeOther, ReasonPhrase: "See Other"},
+	{Code: NotModified, ReasonPhrase: "Not Modified"},
+	{Code: UseProxy, ReasonPhrase: "Use Proxy"},
+	{Code: TemporaryRedirect, ReasonPhrase: "Temporary Redirect"},
+	{Code: PermanentRedirect, ReasonPhrase: "Permanent Redirect", CacheableByDefault: true},
+
+	{Code: BadRequest, ReasonPhrase: "Bad Request"},
+	{Code: Unauthorized, ReasonPhrase: "Unauthorized"},
+	{Code: PaymentRequired, ReasonPhrase: "Payment Required"},
+	{Code: Forbidden, ReasonPhrase: "Forbidden"},
+	{Code: NotFound, ReasonPhrase: "Not Found", CacheableByDefault: true},
+	{Code: MethodNotAllowed, ReasonPhrase: "Method Not Allowed", CacheableByDefault: true},
+	{Code: NotAcceptable, ReasonPhrase: "Not Acceptable"},
+	{Code: ProxyAuthenticationRequired, ReasonPhrase: "Proxy Authentication Required"},
+	{Code: RequestTimeout, ReasonPhrase: "Request Timeout", Retryable: true},
+	{Code: Conflict, ReasonPhrase: "Conflict"},
+	{Code: Gone, ReasonPhrase: "Gone", CacheableByDefault: true},
+	{Code: LengthRequired, ReasonPhrase: "Length Required"},
+	{Code: PreconditionFailed, ReasonPhrase: "Precondition Failed"},
+	{Code: ContentTooLarge, ReasonPhrase: "Content Too Large"},
+	{Code: URITooLong, ReasonPhrase: "URI Too Long", CacheableByDefault: true},
+	{Code: UnsupportedMediaType, ReasonPhrase: "Unsupported Media Type"},
+	{Code: RangeNotSatisfiable, ReasonPhrase: "Range Not Satisfiable"},
+	{Code: ExpectationFailed, ReasonPhrase: "Expectation Failed"},
+	{Code: MisdirectedRequest, ReasonPhrase: "Misdirected Request"},
+	{Code: UnprocessableContent, ReasonPhrase: "Unprocessable Content"},
+	{Code: Locked, ReasonPhrase: "Locked"},
+	{Code: FailedDependency, ReasonPhrase: "Failed Dependency"},
+	{Code: TooEarly, ReasonPhrase: "Too Early", Retryable: true},
+	{Code: UpgradeRequired, ReasonPhrase: "Upgrade Required"},
+	{Code: PreconditionRequired, ReasonPhrase: "Precondition Required"},
+	{Code: TooManyRequests, ReasonPhrase: "Too Many Requests", Retryable: true},
+	{Code: RequestHeaderFieldsTooLarge, ReasonPhrase: "Request Header Fields Too Large"},
+	{Code: UnavailableForLegalReasons, ReasonPhrase: "Unavailable For Legal Reasons"},
+
+	{Code: InternalServerError, ReasonPhrase: "Internal Server Error"},
+	{Code: NotImplemented, ReasonPhrase: "Not Implemented", CacheableByDefault: true},
+	{Code: BadGateway, ReasonPhrase: "Bad Gateway", Retryable: true},
+	{Code: ServiceUnavailable, ReasonPhrase: "Service Unavailable", Retryable: true},
+	{Code: GatewayTimeout, ReasonPhrase: "Gateway Timeout", Retryable: true},
+	{Code: HTTPVersionNotSupported, ReasonPhrase: "HTTP Version Not Supported"},
+	{Code: VariantAlsoNegotiates, ReasonPhrase: "Variant Also Negotiates"},
+	{Code: InsufficientStorage, ReasonPhrase: "Insufficient Storage"},
+	{Code: LoopDetected, ReasonPhrase: "Loop Detected"},
+	{Code: NotExtended, ReasonPhrase: "Not Extended"},
+	{Code: NetworkAuthenticationRequired, ReasonPhrase: "Network Authentication Required"},
+}
+
+var (
+	ianaRegistry = indexStatuses(ianaStatuses)
+	// registry contains the IANA status codes and the codes added with RegisterStatus.
+	registry     = indexStatuses(ianaStatuses)
+	registryLock sync.RWMutex
+)
+
+func indexStatuses(statuses []Status) map[uint16]Status {
+	result := make(map[uint16]Status, len(statuses))
+	for _, s := range statuses {
+		result[s.Code] = s
+	}
+	return result
+}
+
+//endregion
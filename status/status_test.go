@@ -0,0 +1,142 @@
+package status_test
+
+import (
+	"fmt"
+	"testing"
+
+	"go.debugged.it/gsr7/status"
+)
+
+//region Examples
+
+func ExampleReasonPhrase() {
+	fmt.Println(status.ReasonPhrase(status.NotFound))
+	fmt.Println(status.ReasonPhrase(status.UnprocessableContent))
+	// Output: Not Found
+	// Unprocessable Content
+}
+
+func ExampleRegisterStatus() {
+	if err := status.RegisterStatus(status.Status{Code: 499, ReasonPhrase: "Client Closed Request"}); err != nil {
+		panic(err)
+	}
+	fmt.Println(status.ReasonPhrase(499), status.IsClientError(499))
+	// Output: Client Closed Request true
+}
+
+//endregion
+
+//region Tests
+
+func TestClasses(t *testing.T) {
+	testData := []struct {
+		code          uint16
+		informational bool
+		success       bool
+		redirect      bool
+		clientError   bool
+		serverError   bool
+	}{
+		{99, false, false, false, false, false},
+		{100, true, false, false, false, false},
+		{199, true, false, false, false, false},
+		{200, false, true, false, false, false},
+		{226, false, true, false, false, false},
+		{308, false, false, true, false, false},
+		{400, false, false, false, true, false},
+		{499, false, false, false, true, false},
+		{500, false, false, false, false, true},
+		{599, false, false, false, false, true},
+		{600, false, false, false, false, false},
+	}
+	for _, testCase := range testData {
+		t.Run(
+			fmt.Sprintf("%d", testCase.code), func(t *testing.T) {
+				if status.IsInformational(testCase.code) != testCase.informational {
+					t.Fatalf("incorrect IsInformational result")
+				}
+				if status.IsSuccess(testCase.code) != testCase.success {
+					t.Fatalf("incorrect IsSuccess result")
+				}
+				if status.IsRedirect(testCase.code) != testCase.redirect {
+					t.Fatalf("incorrect IsRedirect result")
+				}
+				if status.IsClientError(testCase.code) != testCase.clientError {
+					t.Fatalf("incorrect IsClientError result")
+				}
+				if status.IsServerError(testCase.code) != testCase.serverError {
+					t.Fatalf("incorrect IsServerError result")
+				}
+			},
+		)
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	testData := []struct {
+		code               uint16
+		reasonPhrase       string
+		cacheableByDefault bool
+		retryable          bool
+	}{
+		{status.EarlyHints, "Early Hints", false, false},
+		{status.OK, "OK", true, false},
+		{status.MultiStatus, "Multi-Status", false, false},
+		{status.PermanentRedirect, "Permanent Redirect", true, false},
+		{status.NotFound, "Not Found", true, false},
+		{status.Locked, "Locked", false, false},
+		{status.TooManyRequests, "Too Many Requests", false, true},
+		{status.RequestHeaderFieldsTooLarge, "Request Header Fields Too Large", false, false},
+		{status.UnavailableForLegalReasons, "Unavailable For Legal Reasons", false, false},
+		{status.NotImplemented, "Not Implemented", true, false},
+		{status.ServiceUnavailable, "Service Unavailable", false, true},
+		{status.InsufficientStorage, "Insufficient Storage", false, false},
+		{status.NetworkAuthenticationRequired, "Network Authentication Required", false, false},
+		{418, "", false, false},
+	}
+	for _, testCase := range testData {
+		t.Run(
+			fmt.Sprintf("%d", testCase.code), func(t *testing.T) {
+				if reasonPhrase := status.ReasonPhrase(testCase.code); reasonPhrase != testCase.reasonPhrase {
+					t.Fatalf("incorrect reason phrase: %s", reasonPhrase)
+				}
+				if status.IsCacheableByDefault(testCase.code) != testCase.cacheableByDefault {
+					t.Fatalf("incorrect IsCacheableByDefault result")
+				}
+				if status.IsRetryable(testCase.code) != testCase.retryable {
+					t.Fatalf("incorrect IsRetryable result")
+				}
+			},
+		)
+	}
+}
+
+func TestRegisterStatus(t *testing.T) {
+	custom := status.Status{Code: 520, ReasonPhrase: "Web Server Returned an Unknown Error", Retryable: true}
+	if err := status.RegisterStatus(custom); err != nil {
+		t.Fatalf("failed to register a custom status code (%v)", err)
+	}
+	registered, ok := status.Lookup(520)
+	if !ok || registered != custom {
+		t.Fatalf("the custom status code was not registered: %v", registered)
+	}
+	if !status.IsRetryable(520) {
+		t.Fatalf("the retryable flag of the custom status code was lost")
+	}
+
+	for _, invalid := range []status.Status{
+		{Code: 99, ReasonPhrase: "Too Low"},
+		{Code: 600, ReasonPhrase: "Too High"},
+		{Code: status.NotFound, ReasonPhrase: "Missing"},
+		{Code: 521, ReasonPhrase: "Web Server Is Down\r\n"},
+	} {
+		if err := status.RegisterStatus(invalid); err == nil {
+			t.Fatalf("registering %d did not result in an error", invalid.Code)
+		}
+	}
+	if reasonPhrase := status.ReasonPhrase(status.NotFound); reasonPhrase != "Not Found" {
+		t.Fatalf("the IANA status code was changed: %s", reasonPhrase)
+	}
+}
+
+//endregion
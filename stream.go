@@ -1,14 +1,114 @@
 package gsr7
 
-import "io"
+import (
+	"bytes"
+	"errors"
+	"io"
+	"sync"
+)
 
+//region Interface
+
+// ReadableStream is a body that can be read, such as the body of a received request or response.
 type ReadableStream interface {
-    io.ReadSeekCloser
+	io.ReadSeekCloser
 
-    String() string
-    Bytes() []byte
+	// String returns the full content of the stream as a string, regardless of the current read position.
+	String() string
+	// Bytes returns the full content of the stream, regardless of the current read position.
+	Bytes() []byte
 }
 
+// WritableStream is a body that can be written, such as the body of a request or response that is being sent.
 type WritableStream interface {
-    io.WriteCloser
+	io.WriteCloser
+}
+
+// WritableBuffer is a WritableStream that keeps the written data in memory. Use it as the body of a message that is
+// serialized with WriteClientRequest or WriteServerResponse when the whole body is available in advance.
+type WritableBuffer interface {
+	WritableStream
+
+	// String returns the data written so far as a string.
+	String() string
+	// Bytes returns a copy of the data written so far.
+	Bytes() []byte
+}
+
+// NewReadableStream creates a ReadableStream holding the specified data. The data is copied, so the slice can be
+// modified after the call.
+func NewReadableStream(data []byte) ReadableStream {
+	content := make([]byte, len(data))
+	copy(content, data)
+	return &readableStream{
+		Reader: bytes.NewReader(content),
+		data:   content,
+	}
+}
+
+// NewWritableBuffer creates an empty WritableBuffer. Writing to the buffer after it was closed results in an error.
+func NewWritableBuffer() WritableBuffer {
+	return &writableBuffer{}
 }
+
+//endregion
+
+//region Implementation
+
+type readableStream struct {
+	*bytes.Reader
+	data []byte
+}
+
+func (r readableStream) Close() error {
+	return nil
+}
+
+func (r readableStream) String() string {
+	return string(r.data)
+}
+
+func (r readableStream) Bytes() []byte {
+	result := make([]byte, len(r.data))
+	copy(result, r.data)
+	return result
+}
+
+type writableBuffer struct {
+	lock   sync.Mutex
+	data   []byte
+	closed bool
+}
+
+func (w *writableBuffer) Write(p []byte) (int, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.closed {
+		return 0, errors.New("write to a closed buffer")
+	}
+	w.data = append(w.data, p...)
+	return len(p), nil
+}
+
+func (w *writableBuffer) Close() error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.closed = true
+	return nil
+}
+
+func (w *writableBuffer) String() string {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return string(w.data)
+}
+
+func (w *writableBuffer) Bytes() []byte {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	result := make([]byte, len(w.data))
+	copy(result, w.data)
+	return result
+}
+
+//endregion
@@ -0,0 +1,18 @@
+package gsr7_test
+
+import (
+	"fmt"
+	"io"
+
+	"go.debugged.it/gsr7"
+)
+
+func ExampleNewReadableStream() {
+	stream := gsr7.NewReadableStream([]byte("Hello, World!"))
+	prefix := make([]byte, 5)
+	_, _ = io.ReadFull(stream, prefix)
+	fmt.Println(string(prefix))
+	fmt.Println(stream.String())
+	// Output: Hello
+	// Hello, World!
+}
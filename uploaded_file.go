@@ -0,0 +1,82 @@
+package gsr7
+
+//region Interface
+
+// UploadedFile is a file uploaded as part of a multipart/form-data request body.
+type UploadedFile interface {
+	// GetStream returns the content of the file.
+	GetStream() ReadableStream
+	// GetSize returns the size of the file in bytes.
+	GetSize() int64
+	// GetError returns the error that occurred while receiving the file, for example if the upload was truncated, or
+	// nil if the file was received completely.
+	GetError() error
+	// GetClientFilename returns the file name sent by the client. This value must not be trusted, as it may contain
+	// path separators or other malicious content.
+	GetClientFilename() string
+	// GetClientMediaType returns the media type sent by the client. This value must not be trusted.
+	GetClientMediaType() string
+}
+
+// NewUploadedFile creates an uploaded file from its content and the metadata sent by the client. The size is taken
+// from the content of the stream. A nil stream is treated as an empty file.
+func NewUploadedFile(stream ReadableStream, clientFilename, clientMediaType string) UploadedFile {
+	if stream == nil {
+		stream = NewReadableStream(nil)
+	}
+	return &uploadedFile{
+		stream:          stream,
+		size:            int64(len(stream.Bytes())),
+		clientFilename:  clientFilename,
+		clientMediaType: clientMediaType,
+	}
+}
+
+// NewFailedUploadedFile creates an uploaded file that could not be received completely. The stream contains the
+// received part of the file, if any.
+func NewFailedUploadedFile(stream ReadableStream, clientFilename, clientMediaType string, err error) UploadedFile {
+	if stream == nil {
+		stream = NewReadableStream(nil)
+	}
+	return &uploadedFile{
+		stream:          stream,
+		size:            int64(len(stream.Bytes())),
+		err:             err,
+		clientFilename:  clientFilename,
+		clientMediaType: clientMediaType,
+	}
+}
+
+//endregion
+
+//region Implementation
+
+type uploadedFile struct {
+	stream          ReadableStream
+	size            int64
+	err             error
+	clientFilename  string
+	clientMediaType string
+}
+
+func (u uploadedFile) GetStream() ReadableStream {
+	return u.stream
+}
+
+func (u uploadedFile) GetSize() int64 {
+	return u.size
+}
+
+func (u uploadedFile) GetError() error {
+	return u.err
+}
+
+func (u uploadedFile) GetClientFilename() string {
+	return u.clientFilename
+}
+
+func (u uploadedFile) GetClientMediaType() string {
+	return u.clientMediaType
+}
+
+//endregion
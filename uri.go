@@ -1,22 +1,528 @@
 package gsr7
 
+import (
+	"fmt"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+//region Interface
+
+// URI is an immutable representation of a URI or relative reference as defined in RFC 3986. All getters return the
+// components in their percent-encoded form, exactly as they appear in the URI. All With* methods return a modified
+// copy and never change the original.
+//
+// See https://datatracker.ietf.org/doc/html/rfc3986 for details.
 type URI interface {
-    String() string
-
-    GetScheme() string
-    GetAuthority() string
-    GetUserInfo() string
-    GetHost() string
-    GetPort() *uint16
-    GetPath() string
-    GetQuery() string
-    GetFragment() string
-
-    WithScheme(scheme string) (URI, error)
-    WithUserInfo(user, password string) URI
-    WithHost(host string) (URI, error)
-    WithPort(port *uint16) URI
-    WithPath(path string) (URI, error)
-    WithQuery(query string) (URI, error)
-    WithFragment(fragment string) (URI, error)
+	// Equals compares the normalized forms of two URIs as returned by Normalize.
+	Equals[URI]
+	// Compare orders URIs by comparing their normalized forms as returned by Normalize. This ordering is stable, but
+	// has no meaning beyond making URIs usable as sorted keys.
+	Comparable[URI]
+
+	// String reassembles the URI from its components as described in RFC 3986 section 5.3. A URI parsed with
+	// ParseURI is returned exactly as it was passed in, including empty ports and empty user information. The
+	// password is included, so use Redacted when writing URIs to logs.
+	String() string
+	// Redacted returns the same string as String, except that the password is replaced by "xxxxx".
+	Redacted() string
+	// Formatter formats the URI for the fmt package. The %v, %s and %q verbs use the redacted form, since URIs often
+	// end up in logs. The + flag, as in %+v, prints the full URI including the password.
+	fmt.Formatter
+
+	// GetScheme returns the scheme of the URI without the trailing colon, or an empty string if the URI is a relative
+	// reference.
+	GetScheme() string
+	// GetAuthority returns the authority in the [user-info@]host[:port] form, or an empty string if the URI has no
+	// authority.
+	GetAuthority() string
+	// GetUserInfo returns the user information in the user[:password] form, or an empty string if none is present.
+	GetUserInfo() string
+	// GetUser returns the decoded user name from the user information. If the URI has no user information false is
+	// returned.
+	GetUser() (string, bool)
+	// GetPassword returns the decoded password from the user information. If the user information contains no
+	// password false is returned. An empty password, as in "user:@host", is returned as an empty string and true.
+	GetPassword() (string, bool)
+	// GetHost returns the host of the URI. IP literals are returned including the enclosing brackets. Internationalized
+	// domain names set using WithHost are returned in their A-label (xn--) form.
+	GetHost() string
+	// GetHostKind returns the syntactic form of the host. Hosts that are not IP addresses are reported as registered
+	// names, even if they are empty.
+	GetHostKind() HostKind
+	// GetHostIP returns the IP address of the host if the host is an IPv4 address or an IPv6 literal. The zone
+	// identifier of an IPv6 literal is decoded into the zone of the returned address. For all other hosts false is
+	// returned.
+	GetHostIP() (netip.Addr, bool)
+	// GetUnicodeHost returns the host of the URI for display purposes. A-labels are converted to their Unicode form and
+	// percent-encoded UTF-8 is decoded. If the host is not a valid internationalized domain name it is returned as it
+	// is.
+	GetUnicodeHost() string
+	// GetRegistrableDomain returns the registrable domain of the host according to the Public Suffix List, which is
+	// the public suffix plus one more label, such as "example.co.uk" for "www.example.co.uk". If the host is not a
+	// registered name, or is itself a public suffix, false is returned.
+	GetRegistrableDomain() (string, bool)
+	// GetPort returns the port of the URI, or nil if no port is present.
+	GetPort() *uint16
+	// GetPath returns the path of the URI. The path may be empty, absolute (starting with a slash) or rootless.
+	GetPath() string
+	// GetQuery returns the query string of the URI without the leading question mark.
+	GetQuery() string
+	// GetFragment returns the fragment of the URI without the leading hash mark.
+	GetFragment() string
+
+	// WithScheme returns a copy of the URI with the specified scheme. An empty scheme turns the URI into a relative
+	// reference. An error is returned if the scheme is not valid according to RFC 3986 section 3.1.
+	WithScheme(scheme string) (URI, error)
+	// WithUserInfo returns a copy of the URI with the specified user and password. An empty user removes the user
+	// information, an empty password omits the password. The user and password are decoded values: characters that
+	// are not allowed in the user information, including %, are percent-encoded, so GetUser and GetPassword return
+	// them unchanged.
+	WithUserInfo(user, password string) URI
+	// WithHost returns a copy of the URI with the specified host. An empty host removes the authority, including the
+	// user information and the port.
+	//
+	// IP literals are passed with brackets, as in "[::1]" or "[v7.fe80::a]". Bare IPv6 addresses such as "::1" are
+	// enclosed in brackets automatically. Zone identifiers are encoded as described in RFC 6874, so both
+	// "fe80::1%eth0" and "[fe80::1%eth0]" result in "[fe80::1%25eth0]".
+	//
+	// Hosts containing non-ASCII characters or A-labels are processed according to UTS #46: they are mapped,
+	// validated and stored in their A-label form, so "bücher.example" becomes "xn--bcher-kva.example". Other
+	// characters that are not allowed in a host are percent-encoded. An error is returned if an internationalized
+	// domain name or an IP literal is malformed, or if the path of the URI is not compatible with an authority.
+	//
+	// See https://www.unicode.org/reports/tr46/ for details.
+	WithHost(host string) (URI, error)
+	// WithPort returns a copy of the URI with the specified port. A nil port removes the port. The port is only
+	// rendered if the URI has a host.
+	WithPort(port *uint16) URI
+	// WithPath returns a copy of the URI with the specified path. Characters that are not allowed in a path are
+	// percent-encoded, existing percent-encoded triplets are kept. An error is returned if the path cannot be combined
+	// with the other components as described in RFC 3986 section 3.3.
+	WithPath(path string) (URI, error)
+	// GetPathSegments returns the decoded segments of the path. A leading slash is not represented as a segment, so
+	// "/a/b" results in ["a", "b"], while a trailing slash results in a trailing empty segment: "/a/b/" results in
+	// ["a", "b", ""]. An encoded slash (%2F) is decoded into the segment it appears in. An empty path results in no
+	// segments.
+	GetPathSegments() []string
+	// WithPathSegments returns a copy of the URI with the path built from the specified decoded segments. Each segment
+	// is percent-encoded separately, so a slash in a segment is encoded as %2F instead of separating segments. The path
+	// is absolute if the URI has an authority or the current path is absolute. An error is returned if the path cannot
+	// be combined with the other components, for example if an absolute path without an authority would start with an
+	// empty segment.
+	WithPathSegments(segments ...string) (URI, error)
+	// JoinPath returns a copy of the URI with the specified decoded elements appended to the path. Unlike
+	// WithPathSegments, a slash in an element separates segments. A trailing slash on the current path does not
+	// produce an empty segment, and a trailing slash on the last element is kept. Empty segments and "." are dropped,
+	// and ".." removes the preceding segment. The existing path keeps its encoding.
+	JoinPath(elements ...string) URI
+	// WithQuery returns a copy of the URI with the specified query string. An empty query removes the query.
+	// Characters that are not allowed in a query are percent-encoded, existing percent-encoded triplets are kept.
+	WithQuery(query string) (URI, error)
+	// WithFragment returns a copy of the URI with the specified fragment. An empty fragment removes the fragment.
+	// Characters that are not allowed in a fragment are percent-encoded, existing percent-encoded triplets are kept.
+	WithFragment(fragment string) (URI, error)
+
+	// GetQueryValues parses the query string into a Query. A plus sign is decoded as a space as is customary for query
+	// strings on the web.
+	GetQueryValues() Query
+	// WithQueryValues returns a copy of the URI with the query string replaced by the encoded form of the passed Query.
+	// Parameters that were parsed from a query string and were not changed keep their original encoding. An empty
+	// Query removes the query string.
+	WithQueryValues(query Query) URI
+
+	// ResolveReference resolves the passed reference against the current URI as a base and returns the target URI.
+	// The reference may be relative or absolute. The current URI should be absolute, but this is not enforced.
+	//
+	// See https://datatracker.ietf.org/doc/html/rfc3986#section-5.2 for details.
+	ResolveReference(ref URI) URI
+	// ResolveReferenceString parses the passed reference and resolves it against the current URI as a base. If the
+	// reference cannot be parsed an error is returned.
+	//
+	// See https://datatracker.ietf.org/doc/html/rfc3986#section-5.2 for details.
+	ResolveReferenceString(ref string) (URI, error)
+
+	// ToURL converts the URI into a *url.URL. If the URI cannot be represented as a URL without loss a panic is thrown.
+	ToURL() *url.URL
+	// ToURLE converts the URI into a *url.URL. The result is equivalent to parsing the string form of the URI with
+	// url.Parse, so opaque URIs result in URL.Opaque, an empty query in ForceQuery, and "scheme:/path" in OmitHost.
+	// An error is returned if url.URL cannot represent the URI, which is the case for an empty fragment ("#"), since
+	// url.URL does not distinguish it from a missing one.
+	ToURLE() (*url.URL, error)
+
+	// Normalize returns the normalized form of the URI using the syntax-based and scheme-based normalization from RFC
+	// 3986 section 6.2.2 and 6.2.3. The scheme and host are converted to lowercase, the hexadecimal digits of
+	// percent-encoded triplets to uppercase, percent-encoded unreserved characters are decoded, dot segments are
+	// removed and the default port of the scheme is dropped. For http and https an empty path is replaced by "/".
+	//
+	// See https://datatracker.ietf.org/doc/html/rfc3986#section-6.2 for details.
+	Normalize() URI
 }
+
+// NewURI creates an empty URI reference. The components can be added using the With* methods.
+func NewURI() URI {
+	return &uri{}
+}
+
+// ParseURI parses a URI or relative reference according to RFC 3986. If the string is not valid a panic is thrown.
+func ParseURI(uriString string) URI {
+	return Must(ParseURIE(uriString))
+}
+
+// ParseURIE parses a URI or relative reference according to RFC 3986. If the string is not valid an error is
+// returned. Unlike the With* methods the parser does not percent-encode invalid characters, but rejects them. The
+// only exception are data: URIs, where invalid characters in the path and query are percent-encoded. Use
+// ParseDataURI to decode their payload.
+func ParseURIE(uriString string) (URI, error) {
+	u := &uri{}
+	rest := uriString
+	if i := strings.IndexAny(rest, ":/?#"); i > 0 && rest[i] == ':' {
+		u.scheme = rest[:i]
+		rest = rest[i+1:]
+	}
+	if i := strings.IndexByte(rest, '#'); i >= 0 {
+		u.fragment = rest[i+1:]
+		u.hasFragment = true
+		rest = rest[:i]
+	}
+	if i := strings.IndexByte(rest, '?'); i >= 0 {
+		u.query = rest[i+1:]
+		u.hasQuery = true
+		rest = rest[:i]
+	}
+	if strings.HasPrefix(rest, "//") {
+		rest = rest[2:]
+		authority := rest
+		if i := strings.IndexByte(rest, '/'); i >= 0 {
+			authority = rest[:i]
+			rest = rest[i:]
+		} else {
+			rest = ""
+		}
+		if err := u.parseAuthority(authority); err != nil {
+			return nil, fmt.Errorf("failed to parse URI %s (%w)", uriString, err)
+		}
+	}
+	u.path = rest
+	if strings.EqualFold(u.scheme, "data") {
+		// data: URIs frequently contain characters that are not allowed in URIs, such as spaces and angle brackets
+		// in inline SVG images. These are percent-encoded instead of rejected.
+		u.path = percentEncode(u.path, isPathChar)
+		u.query = percentEncode(u.query, isQueryChar)
+	}
+
+	if err := validate(
+		validateURIScheme(u.scheme),
+		validateURIComponent("user info", u.userInfo, isUserInfoChar),
+		validateURIHost(u.host),
+		validateURIComponent("path", u.path, isPathChar),
+		validateURIPath(u.scheme, u.hasAuthority, u.path),
+		validateURIComponent("query", u.query, isQueryChar),
+		validateURIComponent("fragment", u.fragment, isQueryChar),
+	); err != nil {
+		return nil, fmt.Errorf("failed to parse URI %s (%w)", uriString, err)
+	}
+	return u, nil
+}
+
+//endregion
+
+//region Implementation
+
+type uri struct {
+	scheme       string
+	hasAuthority bool
+	userInfo     string
+	// emptyUserInfo is true if the parsed authority contained an empty user information, as in "http://@host/".
+	emptyUserInfo bool
+	host          string
+	port          *uint16
+	// rawPort is the port as it appeared in the parsed authority, which may be empty or have leading zeros. It is
+	// only used while it denotes the same port as port.
+	rawPort     *string
+	path        string
+	hasQuery    bool
+	query       string
+	hasFragment bool
+	fragment    string
+}
+
+func (u *uri) parseAuthority(authority string) error {
+	u.hasAuthority = true
+	if i := strings.LastIndexByte(authority, '@'); i >= 0 {
+		u.userInfo = authority[:i]
+		u.emptyUserInfo = i == 0
+		authority = authority[i+1:]
+	}
+	port := ""
+	hasPort := false
+	if strings.HasPrefix(authority, "[") {
+		end := strings.IndexByte(authority, ']')
+		if end < 0 {
+			return fmt.Errorf("unterminated IP literal in URI host: %s", authority)
+		}
+		u.host = authority[:end+1]
+		rest := authority[end+1:]
+		if rest != "" {
+			if rest[0] != ':' {
+				return fmt.Errorf("invalid character after IP literal in URI host: %s", authority)
+			}
+			port = rest[1:]
+			hasPort = true
+		}
+	} else if i := strings.IndexByte(authority, ':'); i >= 0 {
+		u.host = authority[:i]
+		port = authority[i+1:]
+		hasPort = true
+	} else {
+		u.host = authority
+	}
+	if port != "" {
+		for i := 0; i < len(port); i++ {
+			if !isDIGIT(port[i]) {
+				return fmt.Errorf("invalid character in URI port position %d (%d)", i, port[i])
+			}
+		}
+		portNumber, err := strconv.ParseUint(port, 10, 16)
+		if err != nil {
+			return fmt.Errorf("invalid URI port: %s (%w)", port, err)
+		}
+		p := uint16(portNumber)
+		u.port = &p
+	}
+	if hasPort {
+		u.rawPort = &port
+	}
+	return nil
+}
+
+func (u uri) String() string {
+	var b strings.Builder
+	if u.scheme != "" {
+		b.WriteString(u.scheme)
+		b.WriteByte(':')
+	}
+	if u.hasAuthority {
+		b.WriteString("//")
+		b.WriteString(u.GetAuthority())
+	}
+	b.WriteString(u.path)
+	if u.hasQuery {
+		b.WriteByte('?')
+		b.WriteString(u.query)
+	}
+	if u.hasFragment {
+		b.WriteByte('#')
+		b.WriteString(u.fragment)
+	}
+	return b.String()
+}
+
+func (u uri) GetScheme() string {
+	return u.scheme
+}
+
+func (u uri) GetAuthority() string {
+	if !u.hasAuthority {
+		return ""
+	}
+	authority := u.host
+	if u.userInfo != "" || u.emptyUserInfo {
+		authority = u.userInfo + "@" + authority
+	}
+	return authority + u.portSuffix()
+}
+
+// portSuffix returns the port including the colon. The port is written as it was parsed, unless it was changed since.
+func (u uri) portSuffix() string {
+	if u.rawPort != nil {
+		if *u.rawPort == "" && u.port == nil {
+			return ":"
+		}
+		if portNumber, err := strconv.ParseUint(*u.rawPort, 10, 16); err == nil && u.port != nil &&
+			uint16(portNumber) == *u.port {
+			return ":" + *u.rawPort
+		}
+	}
+	if u.port == nil {
+		return ""
+	}
+	return ":" + strconv.Itoa(int(*u.port))
+}
+
+func (u uri) GetUserInfo() string {
+	return u.userInfo
+}
+
+func (u uri) GetUser() (string, bool) {
+	if u.userInfo == "" {
+		return "", false
+	}
+	user := u.userInfo
+	if i := strings.IndexByte(user, ':'); i >= 0 {
+		user = user[:i]
+	}
+	return percentDecode(user), true
+}
+
+func (u uri) GetPassword() (string, bool) {
+	i := strings.IndexByte(u.userInfo, ':')
+	if i < 0 {
+		return "", false
+	}
+	return percentDecode(u.userInfo[i+1:]), true
+}
+
+func (u uri) Redacted() string {
+	if i := strings.IndexByte(u.userInfo, ':'); i >= 0 {
+		u.userInfo = u.userInfo[:i+1] + "xxxxx"
+	}
+	return u.String()
+}
+
+func (u uri) Format(f fmt.State, verb rune) {
+	text := u.Redacted()
+	if f.Flag('+') {
+		text = u.String()
+	}
+	// The flags are passed on, except for +, which has a different meaning for strings.
+	format := "%"
+	for _, flag := range "-# 0" {
+		if f.Flag(int(flag)) {
+			format += string(flag)
+		}
+	}
+	if width, ok := f.Width(); ok {
+		format += strconv.Itoa(width)
+	}
+	if precision, ok := f.Precision(); ok {
+		format += "." + strconv.Itoa(precision)
+	}
+	switch verb {
+	case 'v', 's', 'q':
+		_, _ = fmt.Fprintf(f, format+string(verb), text)
+	default:
+		_, _ = fmt.Fprintf(f, "%%!%c(URI=%s)", verb, u.Redacted())
+	}
+}
+
+func (u uri) GetHost() string {
+	return u.host
+}
+
+func (u uri) GetPort() *uint16 {
+	if u.port == nil {
+		return nil
+	}
+	port := *u.port
+	return &port
+}
+
+func (u uri) GetPath() string {
+	return u.path
+}
+
+func (u uri) GetQuery() string {
+	return u.query
+}
+
+func (u uri) GetFragment() string {
+	return u.fragment
+}
+
+func (u uri) WithScheme(scheme string) (URI, error) {
+	if err := validate(
+		validateURIScheme(scheme),
+		validateURIPath(scheme, u.hasAuthority, u.path),
+	); err != nil {
+		return nil, err
+	}
+	u.scheme = scheme
+	return &u, nil
+}
+
+func (u uri) WithUserInfo(user, password string) URI {
+	u.userInfo = ""
+	u.emptyUserInfo = false
+	if user != "" {
+		u.userInfo = percentEncodeAll(user, isUserChar)
+		if password != "" {
+			u.userInfo += ":" + percentEncodeAll(password, isUserInfoChar)
+		}
+	}
+	return &u
+}
+
+func (u uri) WithHost(host string) (URI, error) {
+	host = encodeIPLiteralZone(bracketIPv6Host(host))
+	if !strings.HasPrefix(host, "[") {
+		if needsIDNA(host) {
+			asciiHost, err := toASCIIHost(host)
+			if err != nil {
+				return nil, err
+			}
+			host = asciiHost
+		}
+		host = percentEncode(host, isRegNameChar)
+	}
+	hasAuthority := host != ""
+	if err := validate(
+		validateURIHost(host),
+		validateURIPath(u.scheme, hasAuthority, u.path),
+	); err != nil {
+		return nil, err
+	}
+	u.host = host
+	u.hasAuthority = hasAuthority
+	if !hasAuthority {
+		u.userInfo = ""
+		u.emptyUserInfo = false
+		u.port = nil
+		u.rawPort = nil
+	}
+	return &u, nil
+}
+
+func (u uri) WithPort(port *uint16) URI {
+	u.port = nil
+	u.rawPort = nil
+	if port != nil {
+		p := *port
+		u.port = &p
+	}
+	return &u
+}
+
+func (u uri) WithPath(path string) (URI, error) {
+	path = percentEncode(path, isPathChar)
+	if err := validate(validateURIPath(u.scheme, u.hasAuthority, path)); err != nil {
+		return nil, err
+	}
+	u.path = path
+	return &u, nil
+}
+
+func (u uri) WithQuery(query string) (URI, error) {
+	u.query = percentEncode(query, isQueryChar)
+	u.hasQuery = query != ""
+	return &u, nil
+}
+
+func (u uri) GetQueryValues() Query {
+	// The query string is always validly percent-encoded, so parsing cannot fail.
+	return Must(ParseQueryE(u.query, QueryEncodingForm))
+}
+
+func (u uri) WithQueryValues(query Query) URI {
+	return Must(u.WithQuery(query.String()))
+}
+
+func (u uri) WithFragment(fragment string) (URI, error) {
+	u.fragment = percentEncode(fragment, isQueryChar)
+	u.hasFragment = fragment != ""
+	return &u, nil
+}
+
+//endregion
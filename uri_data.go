@@ -0,0 +1,203 @@
+package gsr7
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+//region Constants
+
+// DataURIEncoding describes how the payload of a data: URI is encoded.
+type DataURIEncoding uint8
+
+const (
+	// DataURIEncodingBase64 encodes the payload using base64. This is the compact choice for binary data.
+	DataURIEncodingBase64 DataURIEncoding = iota
+	// DataURIEncodingPercent percent-encodes every byte of the payload that is not an unreserved character. This keeps
+	// textual payloads readable.
+	DataURIEncodingPercent
+)
+
+// defaultDataURIMediaType is the media type assumed when a data: URI does not specify one.
+const defaultDataURIMediaType = "text/plain"
+
+//endregion
+
+//region Interface
+
+// DataURI is the decoded form of a data: URI as defined in RFC 2397.
+//
+// See https://datatracker.ietf.org/doc/html/rfc2397 for details.
+type DataURI interface {
+	// GetMediaType returns the lowercase media type of the payload without parameters. If the URI does not specify a
+	// media type, text/plain is returned.
+	GetMediaType() string
+	// GetParameters returns a copy of the media type parameters with lowercase names and decoded values. If the URI
+	// does not specify a media type, the charset parameter defaults to US-ASCII.
+	GetParameters() map[string]string
+	// GetEncoding returns the encoding used for the payload in the URI.
+	GetEncoding() DataURIEncoding
+	// GetData returns the decoded payload as a stream.
+	GetData() ReadableStream
+	// GetURI returns the data: URI this structure was decoded from.
+	GetURI() URI
+}
+
+// ParseDataURI decodes a data: URI. If the URI is not a valid data: URI a panic is thrown.
+func ParseDataURI(uri URI) DataURI {
+	return Must(ParseDataURIE(uri))
+}
+
+// ParseDataURIE decodes a data: URI. An error is returned if the URI does not have the data scheme, has no comma
+// separating the media type from the payload, or contains an invalid base64 payload. As described in the WHATWG Fetch
+// standard, whitespace and missing padding in base64 payloads are tolerated, and a fragment is ignored.
+func ParseDataURIE(uri URI) (DataURI, error) {
+	if !strings.EqualFold(uri.GetScheme(), "data") {
+		return nil, fmt.Errorf("not a data URI: %s", uri)
+	}
+	u, err := toURI(uri)
+	if err != nil {
+		return nil, err
+	}
+	content := u.path
+	if u.hasQuery {
+		content += "?" + u.query
+	}
+	comma := strings.IndexByte(content, ',')
+	if comma < 0 {
+		return nil, fmt.Errorf("missing comma in data URI: %s", uri)
+	}
+	result := &dataURI{
+		uri:        uri,
+		parameters: map[string]string{},
+		encoding:   DataURIEncodingPercent,
+	}
+	header := strings.Split(percentDecode(content[:comma]), ";")
+	if last := strings.TrimSpace(header[len(header)-1]); len(header) > 1 && strings.EqualFold(last, "base64") {
+		result.encoding = DataURIEncodingBase64
+		header = header[:len(header)-1]
+	}
+	result.mediaType = strings.ToLower(strings.TrimSpace(header[0]))
+	for _, parameter := range header[1:] {
+		name, value := parameter, ""
+		if i := strings.IndexByte(parameter, '='); i >= 0 {
+			name, value = parameter[:i], parameter[i+1:]
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name != "" {
+			result.parameters[name] = strings.TrimSpace(value)
+		}
+	}
+	if result.mediaType == "" || strings.IndexByte(result.mediaType, '/') < 0 {
+		result.mediaType = defaultDataURIMediaType
+		if _, ok := result.parameters["charset"]; !ok {
+			result.parameters["charset"] = "US-ASCII"
+		}
+	}
+
+	payload := percentDecode(content[comma+1:])
+	if result.encoding == DataURIEncodingBase64 {
+		decoded, err := decodeForgivingBase64(payload)
+		if err != nil {
+			return nil, fmt.Errorf("invalid base64 payload in data URI (%w)", err)
+		}
+		payload = string(decoded)
+	}
+	result.data = []byte(payload)
+	return result, nil
+}
+
+// NewDataURI creates a data: URI from the media type and the payload. The media type may contain parameters, such as
+// "text/plain;charset=utf-8", and may be empty to use the default of text/plain. If the media type is invalid a panic
+// is thrown.
+func NewDataURI(mediaType string, data []byte, encoding DataURIEncoding) URI {
+	return Must(NewDataURIE(mediaType, data, encoding))
+}
+
+// NewDataURIE creates a data: URI from the media type and the payload. The media type may contain parameters, such as
+// "text/plain;charset=utf-8", and may be empty to use the default of text/plain. If the media type is invalid an error
+// is returned.
+func NewDataURIE(mediaType string, data []byte, encoding DataURIEncoding) (URI, error) {
+	if err := validate(validateDataURIMediaType(mediaType)); err != nil {
+		return nil, err
+	}
+	var b strings.Builder
+	b.WriteString(percentEncodeAll(mediaType, isDataURIMediaTypeChar))
+	if encoding == DataURIEncodingBase64 {
+		b.WriteString(";base64,")
+		b.WriteString(base64.StdEncoding.EncodeToString(data))
+	} else {
+		b.WriteByte(',')
+		b.WriteString(percentEncodeAll(string(data), isUnreserved))
+	}
+	path := b.String()
+	if err := validate(
+		validateURIComponent("path", path, isPathChar),
+		validateURIPath("data", false, path),
+	); err != nil {
+		return nil, err
+	}
+	return &uri{
+		scheme: "data",
+		path:   path,
+	}, nil
+}
+
+//endregion
+
+//region Implementation
+
+type dataURI struct {
+	uri        URI
+	mediaType  string
+	parameters map[string]string
+	encoding   DataURIEncoding
+	data       []byte
+}
+
+func (d dataURI) GetMediaType() string {
+	return d.mediaType
+}
+
+func (d dataURI) GetParameters() map[string]string {
+	result := make(map[string]string, len(d.parameters))
+	for name, value := range d.parameters {
+		result[name] = value
+	}
+	return result
+}
+
+func (d dataURI) GetEncoding() DataURIEncoding {
+	return d.encoding
+}
+
+func (d dataURI) GetData() ReadableStream {
+	return NewReadableStream(d.data)
+}
+
+func (d dataURI) GetURI() URI {
+	return d.uri
+}
+
+func isDataURIMediaTypeChar(c byte) bool {
+	return isPathChar(c) && c != ','
+}
+
+// decodeForgivingBase64 decodes base64 while ignoring ASCII whitespace and missing padding.
+//
+// See https://infra.spec.whatwg.org/#forgiving-base64-decode for details.
+func decodeForgivingBase64(s string) ([]byte, error) {
+	s = strings.Map(
+		func(r rune) rune {
+			switch r {
+			case ' ', '\t', '\n', '\f', '\r':
+				return -1
+			}
+			return r
+		}, s,
+	)
+	return base64.RawStdEncoding.DecodeString(strings.TrimRight(s, "="))
+}
+
+//endregion
@@ -0,0 +1,107 @@
+package gsr7_test
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	"go.debugged.it/gsr7"
+)
+
+//region Examples
+
+func ExampleParseDataURI() {
+	data := gsr7.ParseDataURI(gsr7.ParseURI("data:text/plain;charset=utf-8;base64,SGVsbG8sIFdvcmxkIQ=="))
+	fmt.Println(data.GetMediaType())
+	fmt.Println(data.GetParameters()["charset"])
+	fmt.Println(data.GetData().String())
+	// Output: text/plain
+	// utf-8
+	// Hello, World!
+}
+
+func ExampleNewDataURI() {
+	fmt.Println(gsr7.NewDataURI("text/plain", []byte("Hello, World!"), gsr7.DataURIEncodingPercent))
+	fmt.Println(gsr7.NewDataURI("application/octet-stream", []byte{0, 1, 2, 3}, gsr7.DataURIEncodingBase64))
+	// Output: data:text/plain,Hello%2C%20World%21
+	// data:application/octet-stream;base64,AAECAw==
+}
+
+//endregion
+
+//region Tests
+
+func TestParseDataURI(t *testing.T) {
+	testData := []struct {
+		uri        string
+		mediaType  string
+		parameters map[string]string
+		encoding   gsr7.DataURIEncoding
+		data       string
+	}{
+		{"data:,A%20brief%20note", "text/plain", map[string]string{"charset": "US-ASCII"}, gsr7.DataURIEncodingPercent, "A brief note"},
+		{"data:;base64,SGk=", "text/plain", map[string]string{"charset": "US-ASCII"}, gsr7.DataURIEncodingBase64, "Hi"},
+		{"data:text/plain;charset=iso-8859-7,%be%fg%be", "text/plain", map[string]string{"charset": "iso-8859-7"}, gsr7.DataURIEncodingPercent, "\xbe%fg\xbe"},
+		{"data:Image/PNG;base64,iVBO Rw0K", "image/png", map[string]string{}, gsr7.DataURIEncodingBase64, "\x89PNG\r\n"},
+		{"data:image/svg+xml,<svg xmlns=\"http://www.w3.org/2000/svg\"/>", "image/svg+xml", map[string]string{}, gsr7.DataURIEncodingPercent, "<svg xmlns=\"http://www.w3.org/2000/svg\"/>"},
+		{"data:text/plain,a?b#fragment", "text/plain", map[string]string{}, gsr7.DataURIEncodingPercent, "a?b"},
+		{"DATA:text/plain;base64,SGk", "text/plain", map[string]string{}, gsr7.DataURIEncodingBase64, "Hi"},
+	}
+	for _, testCase := range testData {
+		t.Run(
+			testCase.uri, func(t *testing.T) {
+				uri, err := gsr7.ParseURIE(testCase.uri)
+				if err != nil {
+					t.Fatalf("failed to parse %s (%v)", testCase.uri, err)
+				}
+				data, err := gsr7.ParseDataURIE(uri)
+				if err != nil {
+					t.Fatalf("failed to decode %s (%v)", testCase.uri, err)
+				}
+				assertEquals(t, data.GetMediaType(), testCase.mediaType, "incorrect media type: %s", data.GetMediaType())
+				assertEquals(t, data.GetEncoding(), testCase.encoding, "incorrect encoding: %d", data.GetEncoding())
+				parameters := data.GetParameters()
+				assertEquals(t, len(parameters), len(testCase.parameters), "incorrect parameters: %v", parameters)
+				for name, value := range testCase.parameters {
+					assertEquals(t, parameters[name], value, "incorrect parameter %s: %s", name, parameters[name])
+				}
+				content, err := io.ReadAll(data.GetData())
+				if err != nil {
+					t.Fatalf("failed to read data (%v)", err)
+				}
+				assertEquals(t, string(content), testCase.data, "incorrect data: %q", content)
+			},
+		)
+	}
+}
+
+func TestParseDataURIInvalid(t *testing.T) {
+	for _, input := range []string{"http://example.com/", "data:text/plain", "data:;base64,!!!!"} {
+		t.Run(
+			input, func(t *testing.T) {
+				if _, err := gsr7.ParseDataURIE(gsr7.ParseURI(input)); err == nil {
+					t.Fatalf("decoding %s did not result in an error", input)
+				}
+			},
+		)
+	}
+}
+
+func TestNewDataURIRoundTrip(t *testing.T) {
+	payload := []byte("\x00binary, data with spaces & symbols\xff")
+	for _, encoding := range []gsr7.DataURIEncoding{gsr7.DataURIEncodingBase64, gsr7.DataURIEncodingPercent} {
+		uri := gsr7.NewDataURI("application/x-test;name=a b", payload, encoding)
+		parsed := gsr7.ParseDataURI(gsr7.ParseURI(uri.String()))
+		assertEquals(t, parsed.GetMediaType(), "application/x-test", "incorrect media type: %s", parsed.GetMediaType())
+		assertEquals(t, parsed.GetParameters()["name"], "a b", "incorrect parameter: %v", parsed.GetParameters())
+		assertEquals(t, parsed.GetData().String(), string(payload), "incorrect data: %q", parsed.GetData().String())
+	}
+	if _, err := gsr7.NewDataURIE("text/plain,", nil, gsr7.DataURIEncodingBase64); err == nil {
+		t.Fatalf("invalid media type did not result in an error")
+	}
+	if _, err := gsr7.NewDataURIE("//example.com/x", nil, gsr7.DataURIEncodingPercent); err == nil {
+		t.Fatalf("a media type resulting in an invalid path did not result in an error")
+	}
+}
+
+//endregion
@@ -0,0 +1,159 @@
+package gsr7
+
+import (
+	"fmt"
+	"strings"
+)
+
+// uriCharset describes which bytes may appear unencoded in a URI component.
+type uriCharset func(c byte) bool
+
+const upperHex = "0123456789ABCDEF"
+
+func isAnyChar(_ byte) bool {
+	return true
+}
+
+func isALPHA(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDIGIT(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isHEXDIG(c byte) bool {
+	return isDIGIT(c) || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+// isUnreserved checks for the unreserved characters in RFC 3986 section 2.3.
+func isUnreserved(c byte) bool {
+	return isALPHA(c) || isDIGIT(c) || c == '-' || c == '.' || c == '_' || c == '~'
+}
+
+// isSubDelim checks for the sub-delims characters in RFC 3986 section 2.2.
+func isSubDelim(c byte) bool {
+	switch c {
+	case '!', '$', '&', '\'', '(', ')', '*', '+', ',', ';', '=':
+		return true
+	}
+	return false
+}
+
+// isPChar checks for the pchar characters in RFC 3986 section 3.3, excluding percent-encoded triplets.
+func isPChar(c byte) bool {
+	return isUnreserved(c) || isSubDelim(c) || c == ':' || c == '@'
+}
+
+func isUserInfoChar(c byte) bool {
+	return isUnreserved(c) || isSubDelim(c) || c == ':'
+}
+
+func isUserChar(c byte) bool {
+	return isUnreserved(c) || isSubDelim(c)
+}
+
+func isRegNameChar(c byte) bool {
+	return isUnreserved(c) || isSubDelim(c)
+}
+
+func isPathChar(c byte) bool {
+	return isPChar(c) || c == '/'
+}
+
+func isQueryChar(c byte) bool {
+	return isPChar(c) || c == '/' || c == '?'
+}
+
+func isSchemeChar(c byte) bool {
+	return isALPHA(c) || isDIGIT(c) || c == '+' || c == '-' || c == '.'
+}
+
+func unhex(c byte) byte {
+	switch {
+	case c >= '0' && c <= '9':
+		return c - '0'
+	case c >= 'a' && c <= 'f':
+		return c - 'a' + 10
+	default:
+		return c - 'A' + 10
+	}
+}
+
+// isPercentEncoded returns true if s contains a valid percent-encoded triplet at position i.
+func isPercentEncoded(s string, i int) bool {
+	return i+2 < len(s) && s[i] == '%' && isHEXDIG(s[i+1]) && isHEXDIG(s[i+2])
+}
+
+// percentEncode encodes every byte in s that is not allowed by the charset. Existing valid percent-encoded triplets
+// are kept as they are so already encoded input is not encoded twice, while a lone % is encoded as %25.
+func percentEncode(s string, allowed uriCharset) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case isPercentEncoded(s, i):
+			b.WriteString(s[i : i+3])
+			i += 2
+		case c != '%' && allowed(c):
+			b.WriteByte(c)
+		default:
+			b.WriteByte('%')
+			b.WriteByte(upperHex[c>>4])
+			b.WriteByte(upperHex[c&15])
+		}
+	}
+	return b.String()
+}
+
+// percentEncodeAll encodes every byte in s that is not allowed by the charset, including the % character itself.
+// This is used when s is known to be a decoded value.
+func percentEncodeAll(s string, allowed uriCharset) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c != '%' && allowed(c) {
+			b.WriteByte(c)
+			continue
+		}
+		b.WriteByte('%')
+		b.WriteByte(upperHex[c>>4])
+		b.WriteByte(upperHex[c&15])
+	}
+	return b.String()
+}
+
+// percentDecode decodes all percent-encoded triplets in s. Invalid triplets are left in place.
+func percentDecode(s string) string {
+	if strings.IndexByte(s, '%') < 0 {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if isPercentEncoded(s, i) {
+			b.WriteByte(unhex(s[i+1])<<4 | unhex(s[i+2]))
+			i += 2
+			continue
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// checkPercentEncoded verifies that s only contains bytes allowed by the charset and valid percent-encoded triplets.
+func checkPercentEncoded(component string, s string, allowed uriCharset) error {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '%' {
+			if !isPercentEncoded(s, i) {
+				return fmt.Errorf("invalid percent-encoding in URI %s at position %d", component, i)
+			}
+			i += 2
+			continue
+		}
+		if !allowed(c) {
+			return fmt.Errorf("invalid character in URI %s position %d (%d)", component, i, c)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package gsr7
+
+import (
+	"net/netip"
+	"strings"
+
+	"go.debugged.it/gsr7/publicsuffix"
+)
+
+//region Constants
+
+// HostKind describes the syntactic form of the host in a URI as defined in RFC 3986 section 3.2.2.
+type HostKind uint8
+
+const (
+	// HostKindNone indicates that the URI has no host.
+	HostKindNone HostKind = iota
+	// HostKindRegName indicates a registered name, such as a DNS name.
+	HostKindRegName
+	// HostKindIPv4 indicates an IPv4 address in dotted-decimal form.
+	HostKindIPv4
+	// HostKindIPv6 indicates an IPv6 literal enclosed in brackets, optionally with a zone identifier.
+	HostKindIPv6
+	// HostKindIPvFuture indicates an IP literal of a future version enclosed in brackets.
+	HostKindIPvFuture
+)
+
+// String returns the name of the host kind.
+func (h HostKind) String() string {
+	switch h {
+	case HostKindNone:
+		return "none"
+	case HostKindRegName:
+		return "reg-name"
+	case HostKindIPv4:
+		return "IPv4"
+	case HostKindIPv6:
+		return "IPv6"
+	case HostKindIPvFuture:
+		return "IPvFuture"
+	default:
+		return "unknown"
+	}
+}
+
+//endregion
+
+//region Implementation
+
+// bracketIPv6Host converts a bare IPv6 address, optionally with a zone identifier separated by a percent sign, into
+// an IP literal. Other hosts are returned unchanged.
+func bracketIPv6Host(host string) string {
+	if strings.HasPrefix(host, "[") || strings.IndexByte(host, ':') < 0 {
+		return host
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil || !addr.Is6() {
+		return host
+	}
+	literal := addr.WithZone("").String()
+	if zone := addr.Zone(); zone != "" {
+		literal += "%25" + percentEncodeAll(zone, isUnreserved)
+	}
+	return "[" + literal + "]"
+}
+
+// encodeIPLiteralZone encodes the zone identifier separator of an IP literal if it was passed with a bare percent
+// sign, such as [fe80::1%eth0].
+func encodeIPLiteralZone(host string) string {
+	i := strings.IndexByte(host, '%')
+	if !strings.HasPrefix(host, "[") || !strings.HasSuffix(host, "]") || i < 0 || strings.HasPrefix(host[i:], "%25") {
+		return host
+	}
+	return host[:i] + "%25" + percentEncode(host[i+1:len(host)-1], isUnreserved) + "]"
+}
+
+func (u uri) GetHostKind() HostKind {
+	switch {
+	case !u.hasAuthority && u.host == "":
+		return HostKindNone
+	case strings.HasPrefix(u.host, "[v") || strings.HasPrefix(u.host, "[V"):
+		return HostKindIPvFuture
+	case strings.HasPrefix(u.host, "["):
+		return HostKindIPv6
+	}
+	if addr, err := netip.ParseAddr(u.host); err == nil && addr.Is4() {
+		return HostKindIPv4
+	}
+	return HostKindRegName
+}
+
+func (u uri) GetHostIP() (netip.Addr, bool) {
+	switch u.GetHostKind() {
+	case HostKindIPv4:
+		return netip.MustParseAddr(u.host), true
+	case HostKindIPv6:
+		literal := u.host[1 : len(u.host)-1]
+		zone := ""
+		if i := strings.Index(literal, "%25"); i >= 0 {
+			zone = percentDecode(literal[i+3:])
+			literal = literal[:i]
+		}
+		return netip.MustParseAddr(literal).WithZone(zone), true
+	default:
+		return netip.Addr{}, false
+	}
+}
+
+func (u uri) GetRegistrableDomain() (string, bool) {
+	if u.GetHostKind() != HostKindRegName || u.host == "" {
+		return "", false
+	}
+	domain, err := publicsuffix.RegistrableDomain(percentDecode(u.host))
+	if err != nil {
+		return "", false
+	}
+	return domain, true
+}
+
+//endregion
@@ -0,0 +1,138 @@
+package gsr7_test
+
+import (
+	"fmt"
+	"net/netip"
+	"testing"
+
+	"go.debugged.it/gsr7"
+)
+
+//region Examples
+
+func ExampleURI_GetHostIP() {
+	uri := gsr7.ParseURI("http://[fe80::1%25eth0]:8080/")
+	ip, ok := uri.GetHostIP()
+	fmt.Println(ip, ok)
+	fmt.Println(uri.GetHostKind())
+	// Output: fe80::1%eth0 true
+	// IPv6
+}
+
+func ExampleURI_WithHost() {
+	uri := gsr7.Must(gsr7.ParseURI("http://localhost:8080/").WithHost("2001:db8::1"))
+	fmt.Println(uri.GetAuthority())
+	// Output: [2001:db8::1]:8080
+}
+
+//endregion
+
+//region Tests
+
+func TestURIGetHostKind(t *testing.T) {
+	testData := map[string]gsr7.HostKind{
+		"/path":                    gsr7.HostKindNone,
+		"http://example.com/":      gsr7.HostKindRegName,
+		"file:///etc/hosts":        gsr7.HostKindRegName,
+		"http://192.0.2.1/":        gsr7.HostKindIPv4,
+		"http://192.0.2.01/":       gsr7.HostKindRegName,
+		"http://1.2.3/":            gsr7.HostKindRegName,
+		"http://[2001:db8::1]/":    gsr7.HostKindIPv6,
+		"http://[fe80::1%25eth0]/": gsr7.HostKindIPv6,
+		"http://[::ffff:1.2.3.4]/": gsr7.HostKindIPv6,
+		"http://[v1.fe80::a+en1]/": gsr7.HostKindIPvFuture,
+	}
+	for input, expected := range testData {
+		t.Run(
+			input, func(t *testing.T) {
+				kind := gsr7.ParseURI(input).GetHostKind()
+				assertEquals(t, kind, expected, "the host kind of %s is %s instead of %s", input, kind, expected)
+			},
+		)
+	}
+}
+
+func TestURIGetHostIP(t *testing.T) {
+	testData := map[string]string{
+		"http://192.0.2.1/":          "192.0.2.1",
+		"http://[2001:db8::1]/":      "2001:db8::1",
+		"http://[fe80::1%25en%301]/": "fe80::1%en01",
+	}
+	for input, expected := range testData {
+		t.Run(
+			input, func(t *testing.T) {
+				ip, ok := gsr7.ParseURI(input).GetHostIP()
+				if !ok {
+					t.Fatalf("no IP address returned for %s", input)
+				}
+				assertEquals(t, ip, netip.MustParseAddr(expected), "the IP of %s is %s instead of %s", input, ip, expected)
+			},
+		)
+	}
+	for _, input := range []string{"http://example.com/", "http://[v1.x]/", "/path"} {
+		if _, ok := gsr7.ParseURI(input).GetHostIP(); ok {
+			t.Fatalf("an IP address was returned for %s", input)
+		}
+	}
+}
+
+func TestURIWithHostIPLiteral(t *testing.T) {
+	testData := map[string]string{
+		"::1":              "[::1]",
+		"[::1]":            "[::1]",
+		"fe80::1%eth0":     "[fe80::1%25eth0]",
+		"[fe80::1%eth0]":   "[fe80::1%25eth0]",
+		"[fe80::1%25eth0]": "[fe80::1%25eth0]",
+		"[v7.abc:def]":     "[v7.abc:def]",
+		"192.0.2.1":        "192.0.2.1",
+	}
+	base := gsr7.ParseURI("http://localhost/")
+	for input, expected := range testData {
+		t.Run(
+			input, func(t *testing.T) {
+				uri, err := base.WithHost(input)
+				if err != nil {
+					t.Fatalf("failed to set host %s (%v)", input, err)
+				}
+				assertEquals(t, uri.GetHost(), expected, "%s was stored as %s", input, uri.GetHost())
+				assertEquals(t, uri.String(), "http://"+expected+"/", "incorrect URI: %s", uri)
+			},
+		)
+	}
+
+	for _, input := range []string{"[::1", "[1.2.3.4]", "[v.abc]", "[vz.abc]", "[fe80::1%25]", "[::g]"} {
+		t.Run(
+			input, func(t *testing.T) {
+				if _, err := base.WithHost(input); err == nil {
+					t.Fatalf("setting the host %s did not result in an error", input)
+				}
+			},
+		)
+	}
+}
+
+func TestURIGetRegistrableDomain(t *testing.T) {
+	testData := map[string]string{
+		"https://www.example.co.uk/": "example.co.uk",
+		"https://Example.COM/":       "example.com",
+		"https://octocat.github.io/": "octocat.github.io",
+		"https://xn--bcher-kva.de/":  "xn--bcher-kva.de",
+		"https://co.uk/":             "",
+		"https://github.io/":         "",
+		"https://192.0.2.1/":         "",
+		"https://[2001:db8::1]/":     "",
+		"mailto:someone@example.com": "",
+		"file:///etc/hosts":          "",
+	}
+	for input, expected := range testData {
+		t.Run(
+			input, func(t *testing.T) {
+				domain, ok := gsr7.ParseURI(input).GetRegistrableDomain()
+				assertEquals(t, ok, expected != "", "incorrect result for %s", input)
+				assertEquals(t, domain, expected, "incorrect registrable domain for %s: %s", input, domain)
+			},
+		)
+	}
+}
+
+//endregion
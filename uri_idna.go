@@ -0,0 +1,56 @@
+package gsr7
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+// hostIDNAProfile is the IDNA profile used for hosts. It applies the UTS #46 mapping and validation with
+// nontransitional processing, which is what current browsers and DNS resolvers use.
+//
+// See https://www.unicode.org/reports/tr46/ for details.
+var hostIDNAProfile = idna.Lookup
+
+// hostDisplayProfile converts A-labels back to U-labels without rejecting hosts that fail validation.
+var hostDisplayProfile = idna.Display
+
+// needsIDNA returns true if the host contains non-ASCII characters or an A-label that needs to be validated.
+func needsIDNA(host string) bool {
+	for i := 0; i < len(host); i++ {
+		if host[i] >= 0x80 {
+			return true
+		}
+	}
+	for _, label := range strings.Split(host, ".") {
+		if len(label) >= 4 && strings.EqualFold(label[:4], "xn--") {
+			return true
+		}
+	}
+	return false
+}
+
+// toASCIIHost converts an internationalized host name to its A-label form.
+func toASCIIHost(host string) (string, error) {
+	asciiHost, err := hostIDNAProfile.ToASCII(host)
+	if err != nil {
+		return "", fmt.Errorf("invalid internationalized host name: %s (%w)", host, err)
+	}
+	return asciiHost, nil
+}
+
+func (u uri) GetUnicodeHost() string {
+	if u.host == "" || strings.HasPrefix(u.host, "[") {
+		return u.host
+	}
+	host := percentDecode(u.host)
+	if !needsIDNA(host) {
+		return u.host
+	}
+	unicodeHost, err := hostDisplayProfile.ToUnicode(host)
+	if err != nil {
+		return u.host
+	}
+	return unicodeHost
+}
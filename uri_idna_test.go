@@ -0,0 +1,82 @@
+package gsr7_test
+
+import (
+	"fmt"
+	"testing"
+
+	"go.debugged.it/gsr7"
+)
+
+//region Examples
+
+func ExampleURI_GetUnicodeHost() {
+	uri := gsr7.Must(gsr7.ParseURI("https://example.com/").WithHost("bücher.example"))
+	fmt.Println(uri)
+	fmt.Println(uri.GetUnicodeHost())
+	// Output: https://xn--bcher-kva.example/
+	// bücher.example
+}
+
+//endregion
+
+//region Tests
+
+func TestURIWithHostIDNA(t *testing.T) {
+	testData := map[string]string{
+		"bücher.example":        "xn--bcher-kva.example",
+		"BÜCHER.example":        "xn--bcher-kva.example",
+		"xn--bcher-kva.example": "xn--bcher-kva.example",
+		"faß.de":                "xn--fa-hia.de",
+		"日本語.jp":                "xn--wgv71a119e.jp",
+		"example．com":           "example.com",
+		"Example.com":           "Example.com",
+		"under_score.example":   "under_score.example",
+	}
+	base := gsr7.ParseURI("http://localhost/")
+	for input, expected := range testData {
+		t.Run(
+			input, func(t *testing.T) {
+				uri, err := base.WithHost(input)
+				if err != nil {
+					t.Fatalf("failed to set host %s (%v)", input, err)
+				}
+				assertEquals(t, uri.GetHost(), expected, "%s was stored as %s", input, uri.GetHost())
+			},
+		)
+	}
+
+	invalidData := []string{
+		"xn--a.example",
+		"a‍b.example",
+		"ex ample.bücher",
+	}
+	for _, input := range invalidData {
+		t.Run(
+			input, func(t *testing.T) {
+				if _, err := base.WithHost(input); err == nil {
+					t.Fatalf("setting the host %s did not result in an error", input)
+				}
+			},
+		)
+	}
+}
+
+func TestURIGetUnicodeHost(t *testing.T) {
+	testData := map[string]string{
+		"http://xn--bcher-kva.example/": "bücher.example",
+		"http://b%C3%BCcher.example/":   "bücher.example",
+		"http://example.com/":           "example.com",
+		"http://[::1]/":                 "[::1]",
+		"http://xn--a.example.com/":     "xn--a.example.com",
+	}
+	for input, expected := range testData {
+		t.Run(
+			input, func(t *testing.T) {
+				result := gsr7.ParseURI(input).GetUnicodeHost()
+				assertEquals(t, result, expected, "the Unicode host of %s is %s instead of %s", input, result, expected)
+			},
+		)
+	}
+}
+
+//endregion
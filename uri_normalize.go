@@ -0,0 +1,71 @@
+package gsr7
+
+import (
+	"strings"
+)
+
+// defaultPorts contains the default ports for well-known schemes. These ports are removed during normalization.
+var defaultPorts = map[string]uint16{
+	"ftp":    21,
+	"gopher": 70,
+	"http":   80,
+	"https":  443,
+	"ws":     80,
+	"wss":    443,
+}
+
+func (u uri) Normalize() URI {
+	u.scheme = strings.ToLower(u.scheme)
+	u.userInfo = normalizePercentEncoding(u.userInfo)
+	u.emptyUserInfo = false
+	u.rawPort = nil
+	u.host = normalizePercentEncoding(strings.ToLower(u.host))
+	if u.port != nil {
+		if defaultPort, ok := defaultPorts[u.scheme]; ok && defaultPort == *u.port {
+			u.port = nil
+		}
+	}
+	u.path = normalizePercentEncoding(u.path)
+	if u.scheme != "" || u.hasAuthority || strings.HasPrefix(u.path, "/") {
+		u.path = protectEmptyAuthority(removeDotSegments(u.path), u.hasAuthority)
+	}
+	if u.hasAuthority && u.path == "" && (u.scheme == "http" || u.scheme == "https") {
+		u.path = "/"
+	}
+	u.query = normalizePercentEncoding(u.query)
+	u.fragment = normalizePercentEncoding(u.fragment)
+	return &u
+}
+
+func (u uri) Equals(other URI) bool {
+	return u.Compare(other) == 0
+}
+
+func (u uri) Compare(other URI) int {
+	return strings.Compare(u.Normalize().String(), other.Normalize().String())
+}
+
+// normalizePercentEncoding converts the hexadecimal digits of percent-encoded triplets to uppercase and decodes
+// percent-encoded unreserved characters as described in RFC 3986 section 6.2.2.1 and 6.2.2.2.
+func normalizePercentEncoding(s string) string {
+	if strings.IndexByte(s, '%') < 0 {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if !isPercentEncoded(s, i) {
+			b.WriteByte(s[i])
+			continue
+		}
+		c := unhex(s[i+1])<<4 | unhex(s[i+2])
+		if isUnreserved(c) {
+			b.WriteByte(c)
+		} else {
+			b.WriteByte('%')
+			b.WriteByte(upperHex[c>>4])
+			b.WriteByte(upperHex[c&15])
+		}
+		i += 2
+	}
+	return b.String()
+}
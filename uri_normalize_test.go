@@ -0,0 +1,111 @@
+package gsr7_test
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"go.debugged.it/gsr7"
+)
+
+//region Examples
+
+func ExampleURI_Normalize() {
+	uri := gsr7.ParseURI("HTTP://www.Example.COM:80/a/./b/../%7euser/%3f")
+	fmt.Println(uri.Normalize())
+	// Output: http://www.example.com/a/~user/%3F
+}
+
+func ExampleURI_Equals() {
+	a := gsr7.ParseURI("https://example.com")
+	b := gsr7.ParseURI("HTTPS://EXAMPLE.com:443/")
+	fmt.Println(a.Equals(b))
+	// Output: true
+}
+
+//endregion
+
+//region Tests
+
+func TestURINormalize(t *testing.T) {
+	testData := map[string]string{
+		"example://a/b/c/%7Bfoo%7D":             "example://a/b/c/%7Bfoo%7D",
+		"eXAMPLE://a/./b/../b/%63/%7bfoo%7d":    "example://a/b/c/%7Bfoo%7D",
+		"http://example.com":                    "http://example.com/",
+		"http://example.com:/":                  "http://example.com/",
+		"http://example.com:80/":                "http://example.com/",
+		"http://example.com:080/":               "http://example.com/",
+		"http://@example.com:8080/":             "http://example.com:8080/",
+		"http://example.com:08080/":             "http://example.com:8080/",
+		"https://example.com:80/":               "https://example.com:80/",
+		"ftp://%75ser@Example.com:21/":          "ftp://user@example.com/",
+		"HTTP://[2001:DB8::1]:8080/%41?%4a#%5a": "http://[2001:db8::1]:8080/A?J#Z",
+		"../a/./b":                              "../a/./b",
+		"/a/./b":                                "/a/b",
+		"mailto:Joe@Example.COM":                "mailto:Joe@Example.COM",
+		"http://a/b%2fc":                        "http://a/b%2Fc",
+		"http:/..//evil.com/x":                  "http:/.//evil.com/x",
+		"/a/..//b":                              "/.//b",
+	}
+	for input, expected := range testData {
+		t.Run(
+			input, func(t *testing.T) {
+				result := gsr7.ParseURI(input).Normalize().String()
+				assertEquals(t, result, expected, "%s was normalized to %s instead of %s", input, result, expected)
+			},
+		)
+	}
+}
+
+func TestURIEquals(t *testing.T) {
+	equal := [][2]string{
+		{"http://example.com", "http://example.com/"},
+		{"http://example.com/~smith/", "http://example.com/%7Esmith/"},
+		{"http://example.com:80/", "HTTP://EXAMPLE.COM/"},
+	}
+	for _, pair := range equal {
+		if !gsr7.ParseURI(pair[0]).Equals(gsr7.ParseURI(pair[1])) {
+			t.Fatalf("%s does not equal %s", pair[0], pair[1])
+		}
+	}
+	notEqual := [][2]string{
+		{"http://example.com/a", "http://example.com/A"},
+		{"http://example.com/", "https://example.com/"},
+		{"http://example.com/?", "http://example.com/"},
+	}
+	for _, pair := range notEqual {
+		if gsr7.ParseURI(pair[0]).Equals(gsr7.ParseURI(pair[1])) {
+			t.Fatalf("%s equals %s", pair[0], pair[1])
+		}
+	}
+}
+
+func TestURICompare(t *testing.T) {
+	assertSmallerThan(
+		t,
+		gsr7.ParseURI("http://a/").Compare(gsr7.ParseURI("http://b/")),
+		0,
+		"http://a/ is not smaller than http://b/",
+	)
+	assertEquals(
+		t,
+		gsr7.ParseURI("http://a").Compare(gsr7.ParseURI("HTTP://A/")),
+		0,
+		"http://a is not equal to HTTP://A/",
+	)
+
+	uris := []gsr7.URI{
+		gsr7.ParseURI("https://c/"),
+		gsr7.ParseURI("https://a/"),
+		gsr7.ParseURI("https://b/"),
+	}
+	sort.Slice(
+		uris, func(i, j int) bool {
+			return uris[i].Compare(uris[j]) < 0
+		},
+	)
+	assertEquals(t, uris[0].GetHost(), "a", "incorrect sort order: %s", uris[0])
+	assertEquals(t, uris[2].GetHost(), "c", "incorrect sort order: %s", uris[2])
+}
+
+//endregion
@@ -0,0 +1,92 @@
+package gsr7
+
+import (
+	"strings"
+)
+
+// splitPath splits the path into its raw segments. See GetPathSegments for the handling of leading and trailing
+// slashes.
+func splitPath(path string) []string {
+	if path == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimPrefix(path, "/"), "/")
+}
+
+// joinPath joins raw segments into a path.
+func joinPath(segments []string, absolute bool) string {
+	path := strings.Join(segments, "/")
+	if absolute && len(segments) > 0 {
+		path = "/" + path
+	}
+	return path
+}
+
+// encodePathSegment percent-encodes a decoded segment at the specified position. In a relative reference a colon in
+// the first segment of a rootless path is encoded so that it is not mistaken for a scheme.
+func (u uri) encodePathSegment(position int, segment string, absolute bool) string {
+	encoded := percentEncodeAll(segment, isPChar)
+	if position == 0 && !absolute && u.scheme == "" {
+		encoded = strings.ReplaceAll(encoded, ":", "%3A")
+	}
+	return encoded
+}
+
+func (u uri) isAbsolutePath() bool {
+	return u.hasAuthority || strings.HasPrefix(u.path, "/")
+}
+
+func (u uri) GetPathSegments() []string {
+	segments := splitPath(u.path)
+	for i, segment := range segments {
+		segments[i] = percentDecode(segment)
+	}
+	return segments
+}
+
+func (u uri) WithPathSegments(segments ...string) (URI, error) {
+	absolute := u.isAbsolutePath()
+	encoded := make([]string, len(segments))
+	for i, segment := range segments {
+		encoded[i] = u.encodePathSegment(i, segment, absolute)
+	}
+	path := joinPath(encoded, absolute)
+	if err := validate(validateURIPath(u.scheme, u.hasAuthority, path)); err != nil {
+		return nil, err
+	}
+	u.path = path
+	return &u, nil
+}
+
+func (u uri) JoinPath(elements ...string) URI {
+	absolute := u.isAbsolutePath()
+	// The existing segments are kept in their original encoding.
+	segments := splitPath(u.path)
+	if len(segments) > 0 && segments[len(segments)-1] == "" {
+		segments = segments[:len(segments)-1]
+	}
+	trailingSlash := false
+	for _, element := range elements {
+		for _, segment := range strings.Split(element, "/") {
+			switch segment {
+			case "", ".":
+			case "..":
+				if len(segments) > 0 && segments[len(segments)-1] != ".." {
+					segments = segments[:len(segments)-1]
+				} else if !absolute {
+					segments = append(segments, segment)
+				}
+			default:
+				segments = append(segments, u.encodePathSegment(len(segments), segment, absolute))
+			}
+		}
+		if element != "" {
+			trailingSlash = strings.HasSuffix(element, "/")
+		}
+	}
+	if trailingSlash || (absolute && len(segments) == 0) {
+		segments = append(segments, "")
+	}
+	u.path = joinPath(segments, absolute)
+	return &u
+}
@@ -0,0 +1,127 @@
+package gsr7_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"go.debugged.it/gsr7"
+)
+
+//region Examples
+
+func ExampleURI_GetPathSegments() {
+	fmt.Printf("%q\n", gsr7.ParseURI("https://example.com/files/a%2Fb/").GetPathSegments())
+	// Output: ["files" "a/b" ""]
+}
+
+func ExampleURI_WithPathSegments() {
+	uri, _ := gsr7.ParseURI("https://example.com/").WithPathSegments("files", "a/b c")
+	fmt.Println(uri)
+	// Output: https://example.com/files/a%2Fb%20c
+}
+
+func ExampleURI_JoinPath() {
+	base := gsr7.ParseURI("https://example.com/api/")
+	fmt.Println(base.JoinPath("users", "42/"))
+	// Output: https://example.com/api/users/42/
+}
+
+//endregion
+
+//region Tests
+
+func TestURIGetPathSegments(t *testing.T) {
+	testData := map[string][]string{
+		"http://example.com":         nil,
+		"http://example.com/":        {""},
+		"http://example.com/a/b":     {"a", "b"},
+		"http://example.com/a/b/":    {"a", "b", ""},
+		"http://example.com/a%2Fb/c": {"a/b", "c"},
+		"http://example.com/a//b":    {"a", "", "b"},
+		"http://example.com/%C3%BC":  {"ü"},
+		"a/b":                        {"a", "b"},
+		"mailto:user@example.com":    {"user@example.com"},
+	}
+	for input, expected := range testData {
+		t.Run(
+			input, func(t *testing.T) {
+				segments := gsr7.ParseURI(input).GetPathSegments()
+				if !reflect.DeepEqual(segments, expected) {
+					t.Fatalf("incorrect segments: %q (expected: %q)", segments, expected)
+				}
+			},
+		)
+	}
+}
+
+func TestURIWithPathSegments(t *testing.T) {
+	testData := []struct {
+		uri      string
+		segments []string
+		expected string
+	}{
+		{"http://example.com", []string{"a", "b"}, "http://example.com/a/b"},
+		{"http://example.com/old", []string{"a/b", "c"}, "http://example.com/a%2Fb/c"},
+		{"http://example.com/old", []string{"a", ""}, "http://example.com/a/"},
+		{"http://example.com/old", []string{""}, "http://example.com/"},
+		{"http://example.com/old", nil, "http://example.com"},
+		{"http://example.com/", []string{"100%", "?#"}, "http://example.com/100%25/%3F%23"},
+		{"/old", []string{"a:b"}, "/a:b"},
+		{"old", []string{"a:b", "c:d"}, "a%3Ab/c:d"},
+		{"urn:old", []string{"a:b"}, "urn:a:b"},
+	}
+	for _, testCase := range testData {
+		t.Run(
+			testCase.uri, func(t *testing.T) {
+				uri, err := gsr7.ParseURI(testCase.uri).WithPathSegments(testCase.segments...)
+				if err != nil {
+					t.Fatalf("failed to set segments %q (%v)", testCase.segments, err)
+				}
+				assertEquals(t, uri.String(), testCase.expected, "incorrect URI: %s", uri)
+				if !reflect.DeepEqual(uri.GetPathSegments(), testCase.segments) {
+					t.Fatalf("the segments did not round trip: %q", uri.GetPathSegments())
+				}
+			},
+		)
+	}
+
+	if _, err := gsr7.ParseURI("file:/old").WithPathSegments("", "a"); err == nil {
+		t.Fatalf("an absolute path starting with an empty segment did not result in an error")
+	}
+}
+
+func TestURIJoinPath(t *testing.T) {
+	testData := []struct {
+		uri      string
+		elements []string
+		expected string
+	}{
+		{"http://example.com", []string{"a"}, "http://example.com/a"},
+		{"http://example.com", nil, "http://example.com/"},
+		{"http://example.com/a", []string{"b"}, "http://example.com/a/b"},
+		{"http://example.com/a/", []string{"b"}, "http://example.com/a/b"},
+		{"http://example.com/a/", []string{"/b/", "/c"}, "http://example.com/a/b/c"},
+		{"http://example.com/a", []string{"b/"}, "http://example.com/a/b/"},
+		{"http://example.com/a", []string{"b/", ""}, "http://example.com/a/b/"},
+		{"http://example.com/a", []string{"b//c"}, "http://example.com/a/b/c"},
+		{"http://example.com/a/b", []string{"../c"}, "http://example.com/a/c"},
+		{"http://example.com/a", []string{"../../c"}, "http://example.com/c"},
+		{"http://example.com/a", []string{"./b"}, "http://example.com/a/b"},
+		{"http://example.com/a%2Fb", []string{"c d"}, "http://example.com/a%2Fb/c%20d"},
+		{"http://example.com/a?q#f", []string{"b"}, "http://example.com/a/b?q#f"},
+		{"a", []string{"b"}, "a/b"},
+		{"", []string{"a:b"}, "a%3Ab"},
+		{"a", []string{"../../b"}, "../b"},
+	}
+	for _, testCase := range testData {
+		t.Run(
+			fmt.Sprintf("%s %q", testCase.uri, testCase.elements), func(t *testing.T) {
+				uri := gsr7.ParseURI(testCase.uri).JoinPath(testCase.elements...)
+				assertEquals(t, uri.String(), testCase.expected, "incorrect URI: %s", uri)
+			},
+		)
+	}
+}
+
+//endregion
@@ -0,0 +1,130 @@
+package gsr7
+
+import (
+	"strings"
+)
+
+// toURI returns the internal representation of a URI. URIs created by other implementations are parsed from their
+// string form so the presence of empty components is not lost.
+func toURI(u URI) (*uri, error) {
+	if internal, ok := u.(*uri); ok {
+		return internal, nil
+	}
+	parsed, err := ParseURIE(u.String())
+	if err != nil {
+		return nil, err
+	}
+	return parsed.(*uri), nil
+}
+
+func (u uri) ResolveReference(ref URI) URI {
+	r := Must(toURI(ref))
+	return u.resolve(*r)
+}
+
+func (u uri) ResolveReferenceString(ref string) (URI, error) {
+	r, err := ParseURIE(ref)
+	if err != nil {
+		return nil, err
+	}
+	return u.resolve(*r.(*uri)), nil
+}
+
+// resolve implements the reference resolution algorithm from RFC 3986 section 5.2.2 in strict mode.
+func (u uri) resolve(r uri) URI {
+	t := r
+	switch {
+	case r.scheme != "":
+		t.path = removeDotSegments(r.path)
+	case r.hasAuthority:
+		t.scheme = u.scheme
+		t.path = removeDotSegments(r.path)
+	default:
+		t.scheme = u.scheme
+		t.hasAuthority = u.hasAuthority
+		t.userInfo = u.userInfo
+		t.emptyUserInfo = u.emptyUserInfo
+		t.host = u.host
+		t.port = u.port
+		t.rawPort = u.rawPort
+		switch {
+		case r.path == "":
+			t.path = u.path
+			if !r.hasQuery {
+				t.hasQuery = u.hasQuery
+				t.query = u.query
+			}
+		case strings.HasPrefix(r.path, "/"):
+			t.path = removeDotSegments(r.path)
+		default:
+			t.path = removeDotSegments(u.mergePath(r.path))
+		}
+	}
+	t.path = protectEmptyAuthority(t.path, t.hasAuthority)
+	return &t
+}
+
+// protectEmptyAuthority prefixes a path starting with "//" with "/." if the URI has no authority. Removing dot
+// segments can produce such a path, for example from "/..//a", and it would otherwise be read as an authority.
+func protectEmptyAuthority(path string, hasAuthority bool) string {
+	if !hasAuthority && strings.HasPrefix(path, "//") {
+		return "/." + path
+	}
+	return path
+}
+
+// mergePath merges a relative-path reference with the path of the base URI as described in RFC 3986 section 5.2.3.
+func (u uri) mergePath(path string) string {
+	if u.hasAuthority && u.path == "" {
+		return "/" + path
+	}
+	if i := strings.LastIndexByte(u.path, '/'); i >= 0 {
+		return u.path[:i+1] + path
+	}
+	return path
+}
+
+// removeDotSegments removes the special "." and ".." segments from a path as described in RFC 3986 section 5.2.4.
+func removeDotSegments(path string) string {
+	if path == "" {
+		return ""
+	}
+	var output []string
+	input := path
+	for input != "" {
+		switch {
+		case strings.HasPrefix(input, "../"):
+			input = input[3:]
+		case strings.HasPrefix(input, "./"):
+			input = input[2:]
+		case strings.HasPrefix(input, "/./"):
+			input = input[2:]
+		case input == "/.":
+			input = "/"
+		case strings.HasPrefix(input, "/../"):
+			input = input[3:]
+			if len(output) > 0 {
+				output = output[:len(output)-1]
+			}
+		case input == "/..":
+			input = "/"
+			if len(output) > 0 {
+				output = output[:len(output)-1]
+			}
+		case input == "." || input == "..":
+			input = ""
+		default:
+			start := 0
+			if input[0] == '/' {
+				start = 1
+			}
+			end := len(input)
+			if i := strings.IndexByte(input[start:], '/'); i >= 0 {
+				end = start + i
+			}
+			output = append(output, input[:end])
+			input = input[end:]
+		}
+	}
+	return strings.Join(output, "")
+}
@@ -0,0 +1,115 @@
+package gsr7_test
+
+import (
+	"fmt"
+	"testing"
+
+	"go.debugged.it/gsr7"
+)
+
+//region Examples
+
+func ExampleURI_ResolveReference() {
+	base := gsr7.ParseURI("https://example.com/articles/2022/index.html")
+	fmt.Println(base.ResolveReference(gsr7.ParseURI("../2021/archive.html?page=2")))
+	// Output: https://example.com/articles/2021/archive.html?page=2
+}
+
+//endregion
+
+//region Tests
+
+// TestURIResolveReference tests reference resolution against the examples in RFC 3986 section 5.4.
+func TestURIResolveReference(t *testing.T) {
+	base := gsr7.ParseURI("http://a/b/c/d;p?q")
+	testData := []struct {
+		ref      string
+		expected string
+	}{
+		// Normal examples, see https://datatracker.ietf.org/doc/html/rfc3986#section-5.4.1
+		{"g:h", "g:h"},
+		{"g", "http://a/b/c/g"},
+		{"./g", "http://a/b/c/g"},
+		{"g/", "http://a/b/c/g/"},
+		{"/g", "http://a/g"},
+		{"//g", "http://g"},
+		{"?y", "http://a/b/c/d;p?y"},
+		{"g?y", "http://a/b/c/g?y"},
+		{"#s", "http://a/b/c/d;p?q#s"},
+		{"g#s", "http://a/b/c/g#s"},
+		{"g?y#s", "http://a/b/c/g?y#s"},
+		{";x", "http://a/b/c/;x"},
+		{"g;x", "http://a/b/c/g;x"},
+		{"g;x?y#s", "http://a/b/c/g;x?y#s"},
+		{"", "http://a/b/c/d;p?q"},
+		{".", "http://a/b/c/"},
+		{"./", "http://a/b/c/"},
+		{"..", "http://a/b/"},
+		{"../", "http://a/b/"},
+		{"../g", "http://a/b/g"},
+		{"../..", "http://a/"},
+		{"../../", "http://a/"},
+		{"../../g", "http://a/g"},
+
+		// Abnormal examples, see https://datatracker.ietf.org/doc/html/rfc3986#section-5.4.2
+		{"../../../g", "http://a/g"},
+		{"../../../../g", "http://a/g"},
+		{"/./g", "http://a/g"},
+		{"/../g", "http://a/g"},
+		{"g.", "http://a/b/c/g."},
+		{".g", "http://a/b/c/.g"},
+		{"g..", "http://a/b/c/g.."},
+		{"..g", "http://a/b/c/..g"},
+		{"./../g", "http://a/b/g"},
+		{"./g/.", "http://a/b/c/g/"},
+		{"g/./h", "http://a/b/c/g/h"},
+		{"g/../h", "http://a/b/c/h"},
+		{"g;x=1/./y", "http://a/b/c/g;x=1/y"},
+		{"g;x=1/../y", "http://a/b/c/y"},
+		{"g?y/./x", "http://a/b/c/g?y/./x"},
+		{"g?y/../x", "http://a/b/c/g?y/../x"},
+		{"g#s/./x", "http://a/b/c/g#s/./x"},
+		{"g#s/../x", "http://a/b/c/g#s/../x"},
+		{"http:g", "http:g"},
+	}
+	for _, testCase := range testData {
+		t.Run(
+			testCase.ref, func(t *testing.T) {
+				result, err := base.ResolveReferenceString(testCase.ref)
+				if err != nil {
+					t.Fatalf("failed to resolve %s (%v)", testCase.ref, err)
+				}
+				assertEquals(
+					t,
+					result.String(),
+					testCase.expected,
+					"resolving %s resulted in %s instead of %s",
+					testCase.ref,
+					result,
+					testCase.expected,
+				)
+			},
+		)
+	}
+}
+
+func TestURIResolveReferenceWithoutAuthority(t *testing.T) {
+	base := gsr7.ParseURI("http:/a/b")
+	for _, ref := range []string{"..//evil.com/x", "/..//evil.com/x", "http:/..//evil.com/x"} {
+		t.Run(
+			ref, func(t *testing.T) {
+				result := gsr7.Must(base.ResolveReferenceString(ref))
+				assertEquals(t, result.String(), "http:/.//evil.com/x", "incorrect resolution: %s", result)
+				assertEquals(t, gsr7.ParseURI(result.String()).GetHost(), "", "the path became an authority")
+			},
+		)
+	}
+}
+
+func TestURIResolveReferenceEmptyBasePath(t *testing.T) {
+	base := gsr7.ParseURI("http://example.com")
+	result := base.ResolveReference(gsr7.ParseURI("foo"))
+	assertEquals(t, result.String(), "http://example.com/foo", "incorrect resolution: %s", result)
+}
+
+//endregion
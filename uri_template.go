@@ -0,0 +1,477 @@
+package gsr7
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+//region Interface
+
+// URITemplate is a parsed URI template as defined in RFC 6570. A template is parsed once and can then be expanded
+// any number of times with different variables. All four levels of the specification are supported, including all
+// operators and the explode and prefix modifiers.
+//
+// See https://datatracker.ietf.org/doc/html/rfc6570 for details.
+type URITemplate interface {
+	// String returns the template in the form it was parsed from.
+	String() string
+	// GetVariables returns the names of all variables referenced in the template in the order of their first
+	// occurrence.
+	GetVariables() []string
+
+	// Expand expands the template with the passed variables and parses the result as a URI. If the expansion fails
+	// or the result is not a valid URI reference a panic is thrown.
+	//
+	// Variables may be strings, booleans or numbers, slices or arrays for list values, and maps with string keys,
+	// []QueryParameter or Query for associative values. Maps are expanded in the order of their sorted keys. A missing
+	// variable, a nil value and an empty list or map are undefined and are skipped during expansion.
+	Expand(variables map[string]any) URI
+	// ExpandE expands the template with the passed variables and parses the result as a URI. An error is returned if
+	// a variable has an unsupported type, a prefix modifier is applied to a list or map, or the result is not a valid
+	// URI reference. See Expand for the supported variable types.
+	ExpandE(variables map[string]any) (URI, error)
+
+	// Match is the inverse of Expand: it checks if the URI could have been produced by expanding the template and
+	// returns the variable values. If the template cannot be reversed a panic is thrown. See MatchE for details.
+	Match(uri URI) (map[string]any, bool)
+	// MatchE is the inverse of Expand: it checks if the URI could have been produced by expanding the template and
+	// returns the variable values. An error is returned if the template is not part of the subset of RFC 6570 that
+	// can be reversed unambiguously:
+	//
+	//   - Prefix modifiers cannot be used, since they discard part of the value.
+	//   - The + and # operators can only be used with a single variable that is not exploded.
+	//   - An exploded variable must be the last variable of its expression.
+	//   - An expression must be followed by the end of the template, by a literal or expression starting with a
+	//     character that cannot appear in its expansion, or by the final literal of the template. The + operator may
+	//     be followed by a ? or # expression, and its expansion ends at the first occurrence of that character.
+	//
+	// Adjacent query expressions such as {?a}{&b} are treated as one, and their parameters may appear in any order.
+	// Templates starting with a slash or a /, ?, # or ; expression are matched against the path, query and fragment
+	// of absolute URIs, which makes them usable for routing.
+	//
+	// Values are returned as a string. Unencoded commas in a non-exploded value are produced by list and associative
+	// values, and result in a []string. Exploded variables result in a map[string]string if all items are
+	// name-value pairs, or a []string otherwise. Variables that expanded to nothing are not present in the map. If an
+	// unnamed expression contains fewer values than variables, as in "/1" for {/a,b}, the values are assigned to the
+	// leading variables and the trailing ones are treated as undefined.
+	MatchE(uri URI) (map[string]any, bool, error)
+}
+
+// ParseURITemplate parses a URI template according to RFC 6570. If the template is not valid a panic is thrown.
+func ParseURITemplate(template string) URITemplate {
+	return Must(ParseURITemplateE(template))
+}
+
+// ParseURITemplateE parses a URI template according to RFC 6570. If the template is not valid an error is returned.
+func ParseURITemplateE(template string) (URITemplate, error) {
+	t := &uriTemplate{
+		template: template,
+	}
+	rest := template
+	offset := 0
+	for rest != "" {
+		start := strings.IndexAny(rest, "{}")
+		if start < 0 {
+			start = len(rest)
+		} else if rest[start] == '}' {
+			return nil, fmt.Errorf("unexpected closing brace in URI template position %d", offset+start)
+		}
+		if start > 0 {
+			if err := validateURITemplateLiteral(rest[:start], offset); err != nil {
+				return nil, err
+			}
+			t.parts = append(t.parts, uriTemplatePart{literal: rest[:start]})
+		}
+		if start == len(rest) {
+			break
+		}
+		end := strings.IndexAny(rest[start+1:], "{}")
+		if end < 0 || rest[start+1+end] == '{' {
+			return nil, fmt.Errorf("unterminated expression in URI template position %d", offset+start)
+		}
+		end += start + 1
+		expression, err := parseURITemplateExpression(rest[start+1 : end])
+		if err != nil {
+			return nil, fmt.Errorf("invalid expression in URI template position %d (%w)", offset+start, err)
+		}
+		t.parts = append(t.parts, uriTemplatePart{expression: expression})
+		offset += end + 1
+		rest = rest[end+1:]
+	}
+	t.matchParts, t.matchErr = t.compileMatcher()
+	return t, nil
+}
+
+//endregion
+
+//region Implementation
+
+// uriTemplateOperator describes the expansion behavior of an operator as listed in RFC 6570 appendix A.
+type uriTemplateOperator struct {
+	operator      byte
+	first         string
+	separator     string
+	named         bool
+	ifEmpty       string
+	allowReserved bool
+}
+
+var uriTemplateOperators = map[byte]uriTemplateOperator{
+	0:   {0, "", ",", false, "", false},
+	'+': {'+', "", ",", false, "", true},
+	'.': {'.', ".", ".", false, "", false},
+	'/': {'/', "/", "/", false, "", false},
+	';': {';', ";", ";", true, "", false},
+	'?': {'?', "?", "&", true, "=", false},
+	'&': {'&', "&", "&", true, "=", false},
+	'#': {'#', "#", ",", false, "", true},
+}
+
+type uriTemplateVarSpec struct {
+	name    string
+	explode bool
+	prefix  int
+}
+
+type uriTemplateExpression struct {
+	operator  uriTemplateOperator
+	variables []uriTemplateVarSpec
+}
+
+type uriTemplatePart struct {
+	literal    string
+	expression *uriTemplateExpression
+}
+
+type uriTemplate struct {
+	template string
+	parts    []uriTemplatePart
+	// matchParts holds the parts prepared for matching, or matchErr if the template cannot be reversed.
+	matchParts []uriTemplatePart
+	matchErr   error
+}
+
+func validateURITemplateLiteral(literal string, offset int) error {
+	for i := 0; i < len(literal); i++ {
+		c := literal[i]
+		switch {
+		case c == '%':
+			if !isPercentEncoded(literal, i) {
+				return fmt.Errorf("invalid percent-encoding in URI template position %d", offset+i)
+			}
+			i += 2
+		case c <= ' ' || c == 0x7F || strings.IndexByte("\"'<>\\^`|", c) >= 0:
+			return fmt.Errorf("invalid character in URI template position %d (%d)", offset+i, c)
+		}
+	}
+	return nil
+}
+
+func parseURITemplateExpression(expression string) (*uriTemplateExpression, error) {
+	if expression == "" {
+		return nil, fmt.Errorf("empty expression")
+	}
+	result := &uriTemplateExpression{
+		operator: uriTemplateOperators[0],
+	}
+	if operator, ok := uriTemplateOperators[expression[0]]; ok && expression[0] != 0 {
+		result.operator = operator
+		expression = expression[1:]
+	} else if strings.IndexByte("=,!@|", expression[0]) >= 0 {
+		return nil, fmt.Errorf("reserved operator %c", expression[0])
+	}
+	for _, varSpec := range strings.Split(expression, ",") {
+		spec := uriTemplateVarSpec{}
+		if strings.HasSuffix(varSpec, "*") {
+			spec.explode = true
+			varSpec = varSpec[:len(varSpec)-1]
+		} else if i := strings.IndexByte(varSpec, ':'); i >= 0 {
+			maxLength := varSpec[i+1:]
+			if maxLength == "" || len(maxLength) > 4 || maxLength[0] == '0' {
+				return nil, fmt.Errorf("invalid prefix modifier %s", maxLength)
+			}
+			for j := 0; j < len(maxLength); j++ {
+				if !isDIGIT(maxLength[j]) {
+					return nil, fmt.Errorf("invalid prefix modifier %s", maxLength)
+				}
+			}
+			spec.prefix, _ = strconv.Atoi(maxLength)
+			varSpec = varSpec[:i]
+		}
+		if err := validateURITemplateVarName(varSpec); err != nil {
+			return nil, err
+		}
+		spec.name = varSpec
+		result.variables = append(result.variables, spec)
+	}
+	return result, nil
+}
+
+// validateURITemplateVarName checks the varname production from RFC 6570 section 2.3.
+func validateURITemplateVarName(name string) error {
+	if name == "" {
+		return fmt.Errorf("empty variable name")
+	}
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case isALPHA(c) || isDIGIT(c) || c == '_':
+		case c == '%' && isPercentEncoded(name, i):
+			i += 2
+		case c == '.' && i > 0 && i < len(name)-1 && name[i-1] != '.':
+		default:
+			return fmt.Errorf("invalid character in variable name %s position %d (%d)", name, i, c)
+		}
+	}
+	return nil
+}
+
+func (t uriTemplate) String() string {
+	return t.template
+}
+
+func (t uriTemplate) GetVariables() []string {
+	var names []string
+	seen := map[string]struct{}{}
+	for _, part := range t.parts {
+		if part.expression == nil {
+			continue
+		}
+		for _, spec := range part.expression.variables {
+			if _, ok := seen[spec.name]; !ok {
+				seen[spec.name] = struct{}{}
+				names = append(names, spec.name)
+			}
+		}
+	}
+	return names
+}
+
+func (t uriTemplate) Expand(variables map[string]any) URI {
+	return Must(t.ExpandE(variables))
+}
+
+func (t uriTemplate) ExpandE(variables map[string]any) (URI, error) {
+	expanded, err := t.expand(variables)
+	if err != nil {
+		return nil, err
+	}
+	result, err := ParseURIE(expanded)
+	if err != nil {
+		return nil, fmt.Errorf("the expansion of URI template %s is not a valid URI (%w)", t.template, err)
+	}
+	return result, nil
+}
+
+func (t uriTemplate) expand(variables map[string]any) (string, error) {
+	var b strings.Builder
+	for _, part := range t.parts {
+		if part.expression == nil {
+			b.WriteString(percentEncode(part.literal, isURITemplateReservedChar))
+			continue
+		}
+		if err := part.expression.expand(&b, variables); err != nil {
+			return "", fmt.Errorf("failed to expand URI template %s (%w)", t.template, err)
+		}
+	}
+	return b.String(), nil
+}
+
+// isURITemplateReservedChar checks for the characters that are allowed unencoded in reserved expansion, which are
+// the unreserved and reserved characters of RFC 3986.
+func isURITemplateReservedChar(c byte) bool {
+	return isUnreserved(c) || isSubDelim(c) || strings.IndexByte(":/?#[]@", c) >= 0
+}
+
+func (o uriTemplateOperator) encode(value string) string {
+	if o.allowReserved {
+		return percentEncode(value, isURITemplateReservedChar)
+	}
+	return percentEncodeAll(value, isUnreserved)
+}
+
+func (e uriTemplateExpression) expand(b *strings.Builder, variables map[string]any) error {
+	first := true
+	for _, spec := range e.variables {
+		value, err := toURITemplateValue(variables[spec.name])
+		if err != nil {
+			return fmt.Errorf("invalid value for variable %s (%w)", spec.name, err)
+		}
+		if value.undefined() {
+			continue
+		}
+		if first {
+			b.WriteString(e.operator.first)
+			first = false
+		} else {
+			b.WriteString(e.operator.separator)
+		}
+		if err := e.expandValue(b, spec, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e uriTemplateExpression) expandValue(b *strings.Builder, spec uriTemplateVarSpec, value uriTemplateValue) error {
+	op := e.operator
+	if value.list == nil && value.pairs == nil {
+		s := *value.scalar
+		if op.named {
+			b.WriteString(spec.name)
+			if s == "" {
+				b.WriteString(op.ifEmpty)
+				return nil
+			}
+			b.WriteByte('=')
+		}
+		if spec.prefix > 0 && utf8.RuneCountInString(s) > spec.prefix {
+			s = string([]rune(s)[:spec.prefix])
+		}
+		b.WriteString(op.encode(s))
+		return nil
+	}
+	if spec.prefix > 0 {
+		return fmt.Errorf("the prefix modifier cannot be applied to the composite variable %s", spec.name)
+	}
+	var items []string
+	if !spec.explode {
+		if value.list != nil {
+			for _, item := range value.list {
+				items = append(items, op.encode(item))
+			}
+		} else {
+			for _, pair := range value.pairs {
+				items = append(items, op.encode(pair.Name), op.encode(pair.Value))
+			}
+		}
+		if op.named {
+			b.WriteString(spec.name)
+			b.WriteByte('=')
+		}
+		b.WriteString(strings.Join(items, ","))
+		return nil
+	}
+	if value.list != nil {
+		for _, item := range value.list {
+			if op.named {
+				items = append(items, e.namedItem(spec.name, item))
+			} else {
+				items = append(items, op.encode(item))
+			}
+		}
+	} else {
+		for _, pair := range value.pairs {
+			if op.named {
+				items = append(items, e.namedItem(op.encode(pair.Name), pair.Value))
+			} else {
+				items = append(items, op.encode(pair.Name)+"="+op.encode(pair.Value))
+			}
+		}
+	}
+	b.WriteString(strings.Join(items, op.separator))
+	return nil
+}
+
+func (e uriTemplateExpression) namedItem(name string, value string) string {
+	if value == "" {
+		return name + e.operator.ifEmpty
+	}
+	return name + "=" + e.operator.encode(value)
+}
+
+// uriTemplateValue is a variable value converted into one of the three value types of RFC 6570 section 2.4. Exactly
+// one of the fields is set for defined values.
+type uriTemplateValue struct {
+	scalar *string
+	list   []string
+	pairs  []QueryParameter
+}
+
+func (v uriTemplateValue) undefined() bool {
+	return v.scalar == nil && len(v.list) == 0 && len(v.pairs) == 0
+}
+
+func toURITemplateScalar(value any) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case fmt.Stringer:
+		return v.String(), true
+	case bool:
+		return strconv.FormatBool(v), true
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(rv.Uint(), 10), true
+	case reflect.String:
+		return rv.String(), true
+	}
+	return "", false
+}
+
+func toURITemplateValue(value any) (uriTemplateValue, error) {
+	if value == nil {
+		return uriTemplateValue{}, nil
+	}
+	switch v := value.(type) {
+	case []QueryParameter:
+		return uriTemplateValue{pairs: v}, nil
+	case Query:
+		return uriTemplateValue{pairs: v.GetParameters()}, nil
+	}
+	if s, ok := toURITemplateScalar(value); ok {
+		return uriTemplateValue{scalar: &s}, nil
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		list := make([]string, 0, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			item, ok := toURITemplateScalar(rv.Index(i).Interface())
+			if !ok {
+				return uriTemplateValue{}, fmt.Errorf("unsupported list item type %T", rv.Index(i).Interface())
+			}
+			list = append(list, item)
+		}
+		return uriTemplateValue{list: list}, nil
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return uriTemplateValue{}, fmt.Errorf("unsupported map key type %s", rv.Type().Key())
+		}
+		keys := make([]string, 0, rv.Len())
+		for _, key := range rv.MapKeys() {
+			keys = append(keys, key.String())
+		}
+		sort.Strings(keys)
+		pairs := make([]QueryParameter, 0, len(keys))
+		for _, key := range keys {
+			item := rv.MapIndex(reflect.ValueOf(key).Convert(rv.Type().Key())).Interface()
+			s, ok := toURITemplateScalar(item)
+			if !ok {
+				return uriTemplateValue{}, fmt.Errorf("unsupported map value type %T", item)
+			}
+			pairs = append(pairs, QueryParameter{Name: key, Value: s})
+		}
+		return uriTemplateValue{pairs: pairs}, nil
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return uriTemplateValue{}, nil
+		}
+		return toURITemplateValue(rv.Elem().Interface())
+	}
+	return uriTemplateValue{}, fmt.Errorf("unsupported value type %T", value)
+}
+
+//endregion
@@ -0,0 +1,319 @@
+package gsr7
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// compileMatcher prepares the template for matching and verifies that it belongs to the subset of RFC 6570 that can
+// be reversed unambiguously. Adjacent query expressions, such as {?a}{&b}, are merged into a single expression since
+// their parameters are identified by name.
+func (t uriTemplate) compileMatcher() ([]uriTemplatePart, error) {
+	var parts []uriTemplatePart
+	for _, part := range t.parts {
+		if part.expression != nil && len(parts) > 0 {
+			previous := parts[len(parts)-1].expression
+			if previous != nil && (previous.operator.operator == '?' || previous.operator.operator == '&') &&
+				part.expression.operator.operator == '&' {
+				merged := &uriTemplateExpression{
+					operator:  previous.operator,
+					variables: append(append([]uriTemplateVarSpec{}, previous.variables...), part.expression.variables...),
+				}
+				parts[len(parts)-1] = uriTemplatePart{expression: merged}
+				continue
+			}
+		}
+		parts = append(parts, part)
+	}
+
+	for i, part := range parts {
+		e := part.expression
+		if e == nil {
+			continue
+		}
+		op := e.operator
+		for j, spec := range e.variables {
+			if spec.prefix > 0 {
+				return nil, fmt.Errorf("the prefix modifier of variable %s cannot be reversed", spec.name)
+			}
+			if spec.explode && j != len(e.variables)-1 {
+				return nil, fmt.Errorf("the exploded variable %s must be the last variable of its expression", spec.name)
+			}
+			if spec.explode && op.allowReserved {
+				return nil, fmt.Errorf("the exploded variable %s cannot be reversed with the %c operator", spec.name, op.operator)
+			}
+		}
+		if op.allowReserved && len(e.variables) > 1 {
+			return nil, fmt.Errorf("expressions with the %c operator can only be reversed with a single variable", op.operator)
+		}
+		if i == len(parts)-1 {
+			continue
+		}
+		next := parts[i+1]
+		if next.expression == nil {
+			if i+1 == len(parts)-1 {
+				// The final literal is matched as a suffix, which is always unambiguous.
+				continue
+			}
+			if e.mayContain(next.literal[0]) {
+				return nil, fmt.Errorf("the literal %s cannot be distinguished from the preceding expression", next.literal)
+			}
+			continue
+		}
+		nextFirst := next.expression.operator.first
+		if nextFirst == "" || e.mayContain(nextFirst[0]) {
+			return nil, fmt.Errorf(
+				"the expression {%c...} cannot be distinguished from the preceding expression",
+				next.expression.operator.operator,
+			)
+		}
+	}
+	return parts, nil
+}
+
+// mayContain returns true if the expansion of the expression may contain the specified character unencoded.
+func (e uriTemplateExpression) mayContain(c byte) bool {
+	op := e.operator
+	switch op.operator {
+	case '+':
+		return c != '?' && c != '#'
+	case '#':
+		return true
+	}
+	if isUnreserved(c) || c == '%' || c == ',' || strings.IndexByte(op.separator, c) >= 0 {
+		return true
+	}
+	if c == '=' {
+		if op.named {
+			return true
+		}
+		for _, spec := range e.variables {
+			if spec.explode {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isRelativeTemplate returns true if the template only describes the path, query and fragment of a URI.
+func (t uriTemplate) isRelativeTemplate() bool {
+	if len(t.parts) == 0 {
+		return false
+	}
+	first := t.parts[0]
+	if first.expression == nil {
+		return strings.HasPrefix(first.literal, "/") && !strings.HasPrefix(first.literal, "//")
+	}
+	return strings.IndexByte("/?#;", first.expression.operator.operator) >= 0
+}
+
+func (t uriTemplate) Match(uri URI) (map[string]any, bool) {
+	variables, ok, err := t.MatchE(uri)
+	if err != nil {
+		panic(err)
+	}
+	return variables, ok
+}
+
+func (t uriTemplate) MatchE(uri URI) (map[string]any, bool, error) {
+	if t.matchErr != nil {
+		return nil, false, t.matchErr
+	}
+	target := uri.String()
+	if t.isRelativeTemplate() && (uri.GetScheme() != "" || uri.GetAuthority() != "") {
+		u := Must(toURI(uri))
+		target = u.path
+		if u.hasQuery {
+			target += "?" + u.query
+		}
+		if u.hasFragment {
+			target += "#" + u.fragment
+		}
+	}
+
+	variables := map[string]any{}
+	pos := 0
+	for i, part := range t.matchParts {
+		if part.expression == nil {
+			literal := percentEncode(part.literal, isURITemplateReservedChar)
+			if !strings.HasPrefix(target[pos:], literal) {
+				return nil, false, nil
+			}
+			pos += len(literal)
+			continue
+		}
+		end, ok := matchBoundary(target, pos, t.matchParts[i+1:])
+		if !ok {
+			return nil, false, nil
+		}
+		if !part.expression.match(target[pos:end], variables) {
+			return nil, false, nil
+		}
+		pos = end
+	}
+	if pos != len(target) {
+		return nil, false, nil
+	}
+	return variables, true, nil
+}
+
+// matchBoundary finds the end of an expression starting at pos by looking for the start of the following parts.
+func matchBoundary(target string, pos int, following []uriTemplatePart) (int, bool) {
+	if len(following) == 0 {
+		return len(target), true
+	}
+	next := following[0]
+	if next.expression == nil {
+		literal := percentEncode(next.literal, isURITemplateReservedChar)
+		if len(following) == 1 {
+			if !strings.HasSuffix(target, literal) || len(target)-len(literal) < pos {
+				return 0, false
+			}
+			return len(target) - len(literal), true
+		}
+		i := strings.Index(target[pos:], literal)
+		if i < 0 {
+			return 0, false
+		}
+		return pos + i, true
+	}
+	if i := strings.IndexByte(target[pos:], next.expression.operator.first[0]); i >= 0 {
+		return pos + i, true
+	}
+	// The next expression expanded to nothing.
+	return matchBoundary(target, pos, following[1:])
+}
+
+// setMatchedVariable stores a matched value. A variable that occurs more than once in the template must have the same
+// value everywhere.
+func setMatchedVariable(variables map[string]any, name string, value any) bool {
+	if existing, ok := variables[name]; ok {
+		return reflect.DeepEqual(existing, value)
+	}
+	variables[name] = value
+	return true
+}
+
+// splitUnencoded splits s at the separator and decodes the resulting parts.
+func splitUnencoded(s string, separator string) []string {
+	parts := strings.Split(s, separator)
+	for i, part := range parts {
+		parts[i] = percentDecode(part)
+	}
+	return parts
+}
+
+// matchValue decodes the value of a non-exploded variable. Unencoded commas can only originate from list and
+// associative values in operators that do not allow reserved characters, so such values are returned as a list.
+func (e uriTemplateExpression) matchValue(raw string) any {
+	if !e.operator.allowReserved && strings.IndexByte(raw, ',') >= 0 {
+		return splitUnencoded(raw, ",")
+	}
+	return percentDecode(raw)
+}
+
+// matchExploded converts the items of an exploded variable into a list, or into a map if every item is a name-value
+// pair.
+func (e uriTemplateExpression) matchExploded(items []string) any {
+	pairs := map[string]string{}
+	for _, item := range items {
+		i := strings.IndexByte(item, '=')
+		if i < 0 {
+			list := make([]string, len(items))
+			for j, listItem := range items {
+				list[j] = percentDecode(listItem)
+			}
+			return list
+		}
+		pairs[percentDecode(item[:i])] = percentDecode(item[i+1:])
+	}
+	return pairs
+}
+
+func (e uriTemplateExpression) match(text string, variables map[string]any) bool {
+	op := e.operator
+	if text == "" {
+		return true
+	}
+	if !strings.HasPrefix(text, op.first) {
+		return false
+	}
+	text = text[len(op.first):]
+	if !op.allowReserved {
+		for i := 0; i < len(text); i++ {
+			if !e.mayContain(text[i]) {
+				return false
+			}
+		}
+	}
+	if op.named {
+		return e.matchNamed(text, variables)
+	}
+	if len(e.variables) == 1 && !e.variables[0].explode {
+		return setMatchedVariable(variables, e.variables[0].name, e.matchValue(text))
+	}
+	items := strings.Split(text, op.separator)
+	for i, spec := range e.variables {
+		if spec.explode {
+			if i >= len(items) {
+				return true
+			}
+			return setMatchedVariable(variables, spec.name, e.matchExploded(items[i:]))
+		}
+		if i >= len(items) {
+			// The remaining variables were undefined when the template was expanded.
+			return true
+		}
+		if !setMatchedVariable(variables, spec.name, e.matchValue(items[i])) {
+			return false
+		}
+	}
+	return len(items) == len(e.variables)
+}
+
+func (e uriTemplateExpression) matchNamed(text string, variables map[string]any) bool {
+	names := map[string]bool{}
+	var exploded *uriTemplateVarSpec
+	for i, spec := range e.variables {
+		if spec.explode {
+			exploded = &e.variables[i]
+		} else {
+			names[spec.name] = false
+		}
+	}
+	var list []string
+	pairs := map[string]string{}
+	for _, item := range strings.Split(text, e.operator.separator) {
+		name, value := item, ""
+		if i := strings.IndexByte(item, '='); i >= 0 {
+			name, value = item[:i], item[i+1:]
+		}
+		name = percentDecode(name)
+		if seen, ok := names[name]; ok {
+			if seen || !setMatchedVariable(variables, name, e.matchValue(value)) {
+				return false
+			}
+			names[name] = true
+			continue
+		}
+		if exploded == nil {
+			return false
+		}
+		if name == exploded.name {
+			list = append(list, percentDecode(value))
+		} else {
+			pairs[name] = percentDecode(value)
+		}
+	}
+	switch {
+	case len(list) > 0 && len(pairs) > 0:
+		return false
+	case len(list) > 0:
+		return setMatchedVariable(variables, exploded.name, list)
+	case len(pairs) > 0:
+		return setMatchedVariable(variables, exploded.name, pairs)
+	}
+	return true
+}
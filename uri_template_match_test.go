@@ -0,0 +1,148 @@
+package gsr7_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"go.debugged.it/gsr7"
+)
+
+//region Examples
+
+func ExampleURITemplate_Match() {
+	template := gsr7.ParseURITemplate("/users/{id}/repos{?page,per_page}")
+	variables, ok := template.Match(gsr7.ParseURI("https://api.example.com/users/octocat/repos?per_page=10&page=2"))
+	fmt.Println(ok, variables["id"], variables["page"], variables["per_page"])
+	// Output: true octocat 2 10
+}
+
+//endregion
+
+//region Tests
+
+func TestURITemplateMatch(t *testing.T) {
+	testData := []struct {
+		template string
+		uri      string
+		expected map[string]any
+	}{
+		{"/users/{id}", "/users/fred", map[string]any{"id": "fred"}},
+		{"/users/{id}", "/users/Hello%20World%21", map[string]any{"id": "Hello World!"}},
+		{"/users/{id}", "https://example.com/users/fred", map[string]any{"id": "fred"}},
+		{"/files/{name}.json", "/files/a.b.json", map[string]any{"name": "a.b"}},
+		{"{/list*}", "/red/green/blue", map[string]any{"list": []string{"red", "green", "blue"}}},
+		{"{/keys*}", "/semi=%3B/dot=.", map[string]any{"keys": map[string]string{"semi": ";", "dot": "."}}},
+		{"{/who,dub}", "/fred/me%2Ftoo", map[string]any{"who": "fred", "dub": "me/too"}},
+		{"{/var,x}/here", "/value/1024/here", map[string]any{"var": "value", "x": "1024"}},
+		{"{/a,b}", "/1", map[string]any{"a": "1"}},
+		{"{/a,b,c}/x", "/1/2/x", map[string]any{"a": "1", "b": "2"}},
+		{"X{.a,b}", "X.1", map[string]any{"a": "1"}},
+		{"{?x,y}", "?y=768&x=1024", map[string]any{"x": "1024", "y": "768"}},
+		{"{?x,y}", "?x=1024", map[string]any{"x": "1024"}},
+		{"{?x,y,empty}", "?x=1024&y=768&empty=", map[string]any{"x": "1024", "y": "768", "empty": ""}},
+		{"{?list}", "?list=red,green,blue", map[string]any{"list": []string{"red", "green", "blue"}}},
+		{"{?list*}", "?list=red&list=green", map[string]any{"list": []string{"red", "green"}}},
+		{"/search{?q}{&page}", "/search?page=2&q=go", map[string]any{"q": "go", "page": "2"}},
+		{"/search{?q,keys*}", "/search?q=go&a=1&b=2", map[string]any{"q": "go", "keys": map[string]string{"a": "1", "b": "2"}}},
+		{"{;x,y,empty}", ";x=1024;y=768;empty", map[string]any{"x": "1024", "y": "768", "empty": ""}},
+		{"X{.var}", "X.value", map[string]any{"var": "value"}},
+		{"{+base}index", "http://example.com/home/index", map[string]any{"base": "http://example.com/home/"}},
+		{"{+path}{?q}", "/foo/bar?q=1", map[string]any{"path": "/foo/bar", "q": "1"}},
+		{"/a{#frag}", "/a#x/y", map[string]any{"frag": "x/y"}},
+		{"{x}/{x}", "/a/a", nil},
+		{"{x}/{x}", "a/a", map[string]any{"x": "a"}},
+		{"http://example.com/{id}", "http://example.com/42", map[string]any{"id": "42"}},
+	}
+	for _, testCase := range testData {
+		t.Run(
+			testCase.template+" "+testCase.uri, func(t *testing.T) {
+				variables, ok, err := gsr7.ParseURITemplate(testCase.template).MatchE(gsr7.ParseURI(testCase.uri))
+				if err != nil {
+					t.Fatalf("failed to match %s against %s (%v)", testCase.uri, testCase.template, err)
+				}
+				if testCase.expected == nil {
+					if ok {
+						t.Fatalf("%s unexpectedly matched %s with %v", testCase.uri, testCase.template, variables)
+					}
+					return
+				}
+				if !ok {
+					t.Fatalf("%s did not match %s", testCase.uri, testCase.template)
+				}
+				if !reflect.DeepEqual(variables, testCase.expected) {
+					t.Fatalf("matching %s against %s resulted in %v", testCase.uri, testCase.template, variables)
+				}
+			},
+		)
+	}
+}
+
+func TestURITemplateNoMatch(t *testing.T) {
+	testData := [][2]string{
+		{"/users/{id}", "/groups/fred"},
+		{"/users/{id}/repos", "/users/fred"},
+		{"{?x}", "?y=1"},
+		{"{?x}", "?x=1&x=2"},
+		{"{/a,b}", "/one/two/three"},
+		{"/files/{name}.json", "/files/a.xml"},
+	}
+	for _, testCase := range testData {
+		t.Run(
+			testCase[0]+" "+testCase[1], func(t *testing.T) {
+				if variables, ok := gsr7.ParseURITemplate(testCase[0]).Match(gsr7.ParseURI(testCase[1])); ok {
+					t.Fatalf("%s unexpectedly matched %s with %v", testCase[1], testCase[0], variables)
+				}
+			},
+		)
+	}
+}
+
+func TestURITemplateMatchNotReversible(t *testing.T) {
+	testData := []string{
+		"{var:3}",
+		"{x}{y}",
+		"{/a}{/b}",
+		"{/a}{.b}",
+		"{+a,b}",
+		"{+list*}",
+		"{#a}{?b}",
+		"{/list*,x}",
+		"{a}x{b}",
+	}
+	for _, template := range testData {
+		t.Run(
+			template, func(t *testing.T) {
+				if _, _, err := gsr7.ParseURITemplate(template).MatchE(gsr7.ParseURI("/")); err == nil {
+					t.Fatalf("matching %s did not result in an error", template)
+				}
+			},
+		)
+	}
+}
+
+// TestURITemplateRoundTrip checks that matching the expansion of a template returns the original variables.
+func TestURITemplateRoundTrip(t *testing.T) {
+	template := gsr7.ParseURITemplate("/users/{id}{/path*}{?q,lang}")
+	variables := map[string]any{
+		"id":   "a b/c",
+		"path": []string{"x", "y&z"},
+		"q":    "1+1=2",
+		"lang": "en",
+	}
+	matched, ok := template.Match(template.Expand(variables))
+	if !ok {
+		t.Fatalf("the expansion of %s did not match", template)
+	}
+	expected := map[string]any{
+		"id":   "a b/c",
+		"path": []string{"x", "y&z"},
+		"q":    "1+1=2",
+		"lang": "en",
+	}
+	if !reflect.DeepEqual(matched, expected) {
+		t.Fatalf("round trip resulted in %v", matched)
+	}
+}
+
+//endregion
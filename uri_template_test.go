@@ -0,0 +1,137 @@
+package gsr7_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.debugged.it/gsr7"
+)
+
+//region Examples
+
+func ExampleParseURITemplate() {
+	template := gsr7.ParseURITemplate("https://api.example.com/users/{id}/repos{?page,per_page}")
+	uri := template.Expand(
+		map[string]any{
+			"id":   "octocat",
+			"page": 2,
+		},
+	)
+	fmt.Println(uri)
+	// Output: https://api.example.com/users/octocat/repos?page=2
+}
+
+func ExampleURITemplate_GetVariables() {
+	template := gsr7.ParseURITemplate("/search{?q,lang}{#q}")
+	fmt.Println(template.GetVariables())
+	// Output: [q lang]
+}
+
+//endregion
+
+//region Tests
+
+// uriTemplateTestGroup is a group of test cases in the format of the uritemplate-test suite. The expected value of a
+// test case is either a string, a list of acceptable strings, or false if the template must fail.
+type uriTemplateTestGroup struct {
+	Level     int              `json:"level"`
+	Variables map[string]any   `json:"variables"`
+	TestCases [][2]interface{} `json:"testcases"`
+}
+
+func loadURITemplateTests(t *testing.T, file string) map[string]uriTemplateTestGroup {
+	data, err := os.ReadFile(filepath.Join("testdata", "uritemplate", file))
+	if err != nil {
+		t.Fatalf("failed to read %s (%v)", file, err)
+	}
+	groups := map[string]uriTemplateTestGroup{}
+	if err := json.Unmarshal(data, &groups); err != nil {
+		t.Fatalf("failed to parse %s (%v)", file, err)
+	}
+	return groups
+}
+
+func TestURITemplateExpand(t *testing.T) {
+	for _, file := range []string{"spec-examples.json", "spec-examples-by-section.json", "additional-examples.json"} {
+		for name, group := range loadURITemplateTests(t, file) {
+			group := group
+			t.Run(
+				name, func(t *testing.T) {
+					for _, testCase := range group.TestCases {
+						template := testCase[0].(string)
+						var expected []string
+						switch e := testCase[1].(type) {
+						case string:
+							expected = []string{e}
+						case []interface{}:
+							for _, option := range e {
+								expected = append(expected, option.(string))
+							}
+						}
+						result, err := gsr7.ParseURITemplate(template).ExpandE(group.Variables)
+						if err != nil {
+							t.Fatalf("failed to expand %s (%v)", template, err)
+						}
+						found := false
+						for _, option := range expected {
+							if result.String() == option {
+								found = true
+							}
+						}
+						if !found {
+							t.Fatalf("%s expanded to %s instead of %v", template, result, expected)
+						}
+					}
+				},
+			)
+		}
+	}
+}
+
+func TestURITemplateFailures(t *testing.T) {
+	for name, group := range loadURITemplateTests(t, "negative-tests.json") {
+		group := group
+		t.Run(
+			name, func(t *testing.T) {
+				for _, testCase := range group.TestCases {
+					template := testCase[0].(string)
+					parsed, err := gsr7.ParseURITemplateE(template)
+					if err != nil {
+						continue
+					}
+					if result, err := parsed.ExpandE(group.Variables); err == nil {
+						t.Fatalf("%s did not fail, but expanded to %s", template, result)
+					}
+				}
+			},
+		)
+	}
+}
+
+func TestURITemplateValueTypes(t *testing.T) {
+	template := gsr7.ParseURITemplate("/{a}{/b*}{?c*}{&d*}{&e}")
+	result := template.Expand(
+		map[string]any{
+			"a": 42,
+			"b": []int{1, 2},
+			"c": map[string]string{"y": "2", "x": "1"},
+			"d": gsr7.ParseQuery("z=3&w=4", gsr7.QueryEncodingRFC3986),
+			"e": true,
+		},
+	)
+	assertEquals(t, result.String(), "/42/1/2?x=1&y=2&z=3&w=4&e=true", "incorrect expansion: %s", result)
+
+	if _, err := template.ExpandE(map[string]any{"a": struct{}{}}); err == nil {
+		t.Fatalf("expanding an unsupported type did not result in an error")
+	}
+}
+
+func TestURITemplateString(t *testing.T) {
+	template := gsr7.ParseURITemplate("http://example.com/{+path}{?q}")
+	assertEquals(t, template.String(), "http://example.com/{+path}{?q}", "incorrect template: %s", template)
+}
+
+//endregion
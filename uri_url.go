@@ -0,0 +1,67 @@
+package gsr7
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// URIFromURL converts a *url.URL into a URI. If the URL cannot be represented as a URI without loss a panic is thrown.
+// See URIFromURLE for details.
+func URIFromURL(u *url.URL) URI {
+	return Must(URIFromURLE(u))
+}
+
+// URIFromURLE converts a *url.URL into a URI. Opaque URLs, user information, the encoded path from RawPath, RawQuery,
+// ForceQuery, OmitHost and RawFragment are all preserved, so converting the result back with ToURL produces an equal
+// URL.
+//
+// The two models differ in the following cases, in which an error is returned:
+//
+//   - url.URL accepts characters that RFC 3986 does not allow, for example spaces or curly braces in RawQuery.
+//   - url.URL allows a Host with a rootless Path, which URL.String silently turns into an absolute path.
+//   - url.URL allows a port above 65535 in Host. URI stores ports as uint16.
+//   - url.URL.String adds a "./" prefix to relative paths with a colon in their first segment and escapes a leading
+//     "//" in paths without an authority, which changes the path on the way back.
+func URIFromURLE(u *url.URL) (URI, error) {
+	result, err := ParseURIE(u.String())
+	if err != nil {
+		return nil, fmt.Errorf("the URL %s is not a valid URI (%w)", u, err)
+	}
+	back, err := url.Parse(result.String())
+	if err != nil || !sameURL(u, back) {
+		return nil, fmt.Errorf("the URL %s cannot be represented as a URI without loss", u)
+	}
+	return result, nil
+}
+
+func (u uri) ToURL() *url.URL {
+	return Must(u.ToURLE())
+}
+
+func (u uri) ToURLE() (*url.URL, error) {
+	result, err := url.Parse(u.String())
+	if err != nil {
+		return nil, fmt.Errorf("the URI %s cannot be converted to a URL (%w)", u, err)
+	}
+	if result.String() != u.String() {
+		return nil, fmt.Errorf("the URI %s cannot be represented as a URL without loss", u)
+	}
+	return result, nil
+}
+
+// sameURL compares all fields of two URLs that influence their meaning. RawPath and RawFragment are compared through
+// EscapedPath and EscapedFragment since url.URL ignores them if they are not a valid encoding of Path and Fragment.
+func sameURL(a, b *url.URL) bool {
+	return a.Scheme == b.Scheme &&
+		a.Opaque == b.Opaque &&
+		a.User.String() == b.User.String() &&
+		(a.User == nil) == (b.User == nil) &&
+		a.Host == b.Host &&
+		a.Path == b.Path &&
+		a.EscapedPath() == b.EscapedPath() &&
+		a.OmitHost == b.OmitHost &&
+		a.ForceQuery == b.ForceQuery &&
+		a.RawQuery == b.RawQuery &&
+		a.Fragment == b.Fragment &&
+		a.EscapedFragment() == b.EscapedFragment()
+}
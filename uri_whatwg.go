@@ -0,0 +1,1021 @@
+package gsr7
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/idna"
+)
+
+// ParseURIWHATWG parses a URL the way browsers do. If base is not nil, input is resolved against it. If the URL cannot
+// be parsed a panic is thrown. See ParseURIWHATWGE for details.
+func ParseURIWHATWG(input string, base URI) URI {
+	return Must(ParseURIWHATWGE(input, base))
+}
+
+// ParseURIWHATWGE parses a URL according to the WHATWG URL Standard, which is what browsers use. If base is not nil,
+// input is resolved against it. Unlike ParseURIE, this parser is forgiving: it strips leading and trailing whitespace
+// and control characters, removes tabs and newlines, treats backslashes as slashes in special schemes such as http,
+// repairs missing slashes ("http:/example.com"), decodes percent-encoded hosts, converts hosts to A-labels, parses IPv4
+// addresses in their shorthand forms, removes default ports and resolves dot segments.
+//
+// The resulting URI serializes exactly as the href of the URL in a browser. Note that browsers leave some characters
+// unencoded that RFC 3986 does not allow, such as "|" in paths or "{" in query strings, so the resulting URI is not
+// necessarily accepted by ParseURIE.
+//
+// See https://url.spec.whatwg.org/#concept-basic-url-parser for details.
+func ParseURIWHATWGE(input string, base URI) (URI, error) {
+	p := &whatwgParser{}
+	if base != nil {
+		b, err := whatwgURLFromURI(base)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse URI %s using the WHATWG URL Standard (%w)", input, err)
+		}
+		p.base = b
+	}
+	if err := p.parse(input); err != nil {
+		return nil, fmt.Errorf("failed to parse URI %s using the WHATWG URL Standard (%w)", input, err)
+	}
+	return p.url.toURI(), nil
+}
+
+//region Data model
+
+// whatwgSpecialSchemes contains the special schemes of the WHATWG URL Standard and their default ports. The file
+// scheme has no default port.
+var whatwgSpecialSchemes = map[string]int{
+	"ftp":   21,
+	"file":  -1,
+	"http":  80,
+	"https": 443,
+	"ws":    80,
+	"wss":   443,
+}
+
+// whatwgURL is the URL record of the WHATWG URL Standard. Unlike the uri struct, the path is kept as a list of segments
+// unless it is an opaque path.
+type whatwgURL struct {
+	scheme      string
+	username    string
+	password    string
+	hasHost     bool
+	host        string
+	port        *uint16
+	path        []string
+	opaquePath  bool
+	opaque      string
+	hasQuery    bool
+	query       string
+	hasFragment bool
+	fragment    string
+}
+
+func (w *whatwgURL) isSpecial() bool {
+	_, ok := whatwgSpecialSchemes[w.scheme]
+	return ok
+}
+
+// shortenPath removes the last path segment, keeping a normalized Windows drive letter in file URLs.
+func (w *whatwgURL) shortenPath() {
+	if w.scheme == "file" && len(w.path) == 1 && isNormalizedWindowsDriveLetter(w.path[0]) {
+		return
+	}
+	if len(w.path) > 0 {
+		w.path = w.path[:len(w.path)-1]
+	}
+}
+
+func (w *whatwgURL) toURI() *uri {
+	u := &uri{
+		scheme:       w.scheme,
+		hasAuthority: w.hasHost,
+		userInfo:     w.username,
+		host:         w.host,
+		port:         w.port,
+		hasQuery:     w.hasQuery,
+		query:        w.query,
+		hasFragment:  w.hasFragment,
+		fragment:     w.fragment,
+	}
+	if w.password != "" {
+		u.userInfo += ":" + w.password
+	}
+	if w.opaquePath {
+		u.path = w.opaque
+		return u
+	}
+	var b strings.Builder
+	if !w.hasHost && len(w.path) > 1 && w.path[0] == "" {
+		// Without the /. prefix the path would be mistaken for an authority.
+		b.WriteString("/.")
+	}
+	for _, segment := range w.path {
+		b.WriteByte('/')
+		b.WriteString(segment)
+	}
+	u.path = b.String()
+	return u
+}
+
+// whatwgURLFromURI converts a URI into a URL record so it can be used as a base URL.
+func whatwgURLFromURI(base URI) (*whatwgURL, error) {
+	u, err := toURI(base)
+	if err != nil {
+		return nil, err
+	}
+	if u.scheme == "" {
+		return nil, fmt.Errorf("the base URI %s has no scheme", base)
+	}
+	w := &whatwgURL{
+		scheme:      strings.ToLower(u.scheme),
+		hasHost:     u.hasAuthority,
+		host:        u.host,
+		port:        u.port,
+		hasQuery:    u.hasQuery,
+		query:       u.query,
+		hasFragment: u.hasFragment,
+		fragment:    u.fragment,
+	}
+	w.username = u.userInfo
+	if i := strings.IndexByte(u.userInfo, ':'); i >= 0 {
+		w.username, w.password = u.userInfo[:i], u.userInfo[i+1:]
+	}
+	path := u.path
+	switch {
+	case !u.hasAuthority && !strings.HasPrefix(path, "/"):
+		w.opaquePath = true
+		w.opaque = path
+	case path != "":
+		if !u.hasAuthority && strings.HasPrefix(path, "/.//") {
+			path = path[2:]
+		}
+		w.path = strings.Split(path[1:], "/")
+	}
+	return w, nil
+}
+
+//endregion
+
+//region Parser
+
+type whatwgState uint8
+
+const (
+	whatwgSchemeStart whatwgState = iota
+	whatwgScheme
+	whatwgNoScheme
+	whatwgSpecialRelativeOrAuthority
+	whatwgPathOrAuthority
+	whatwgRelative
+	whatwgRelativeSlash
+	whatwgSpecialAuthoritySlashes
+	whatwgSpecialAuthorityIgnoreSlashes
+	whatwgAuthority
+	whatwgHost
+	whatwgPort
+	whatwgFile
+	whatwgFileSlash
+	whatwgFileHost
+	whatwgPathStart
+	whatwgPath
+	whatwgOpaquePath
+	whatwgQuery
+	whatwgFragment
+)
+
+// whatwgEOF marks the position after the last code point of the input.
+const whatwgEOF rune = -1
+
+type whatwgParser struct {
+	base    *whatwgURL
+	url     whatwgURL
+	input   []rune
+	pointer int
+}
+
+func (p *whatwgParser) at(i int) rune {
+	if i < 0 || i >= len(p.input) {
+		return whatwgEOF
+	}
+	return p.input[i]
+}
+
+// remainingStartsWith checks if the code points after the current one start with the specified string.
+func (p *whatwgParser) remainingStartsWith(s string) bool {
+	i := p.pointer + 1
+	for _, r := range s {
+		if p.at(i) != r {
+			return false
+		}
+		i++
+	}
+	return true
+}
+
+// remainingFrom returns the input starting at the specified position.
+func (p *whatwgParser) remainingFrom(i int) []rune {
+	if i >= len(p.input) {
+		return nil
+	}
+	return p.input[i:]
+}
+
+// cleanWHATWGInput removes leading and trailing C0 controls and spaces, as well as all tabs and newlines.
+func cleanWHATWGInput(input string) []rune {
+	input = strings.TrimFunc(
+		input, func(r rune) bool {
+			return r <= ' '
+		},
+	)
+	runes := make([]rune, 0, len(input))
+	for _, r := range input {
+		if r != '\t' && r != '\n' && r != '\r' {
+			runes = append(runes, r)
+		}
+	}
+	return runes
+}
+
+// parse runs the basic URL parser state machine. The states and their order follow the specification closely so the
+// two can be compared side by side.
+func (p *whatwgParser) parse(input string) error {
+	p.input = cleanWHATWGInput(input)
+	state := whatwgSchemeStart
+	var buffer []rune
+	atSignSeen, insideBrackets, passwordTokenSeen := false, false, false
+	u := &p.url
+	base := p.base
+
+	for p.pointer = 0; ; p.pointer++ {
+		c := p.at(p.pointer)
+		switch state {
+		case whatwgSchemeStart:
+			if c < utf8.RuneSelf && c >= 0 && isALPHA(byte(c)) {
+				buffer = append(buffer, toLowerASCII(c))
+				state = whatwgScheme
+			} else {
+				state = whatwgNoScheme
+				p.pointer--
+			}
+		case whatwgScheme:
+			switch {
+			case c < utf8.RuneSelf && c >= 0 && isSchemeChar(byte(c)):
+				buffer = append(buffer, toLowerASCII(c))
+			case c == ':':
+				u.scheme = string(buffer)
+				buffer = buffer[:0]
+				switch {
+				case u.scheme == "file":
+					state = whatwgFile
+				case u.isSpecial() && base != nil && base.scheme == u.scheme:
+					state = whatwgSpecialRelativeOrAuthority
+				case u.isSpecial():
+					state = whatwgSpecialAuthoritySlashes
+				case p.remainingStartsWith("/"):
+					state = whatwgPathOrAuthority
+					p.pointer++
+				default:
+					u.opaquePath = true
+					state = whatwgOpaquePath
+				}
+			default:
+				buffer = buffer[:0]
+				state = whatwgNoScheme
+				p.pointer = -1
+			}
+		case whatwgNoScheme:
+			switch {
+			case base == nil || (base.opaquePath && c != '#'):
+				return errors.New("relative URL without a base")
+			case base.opaquePath && c == '#':
+				u.scheme = base.scheme
+				u.opaquePath = true
+				u.opaque = base.opaque
+				u.hasQuery, u.query = base.hasQuery, base.query
+				u.hasFragment = true
+				state = whatwgFragment
+			case base.scheme != "file":
+				state = whatwgRelative
+				p.pointer--
+			default:
+				state = whatwgFile
+				p.pointer--
+			}
+		case whatwgSpecialRelativeOrAuthority:
+			if c == '/' && p.remainingStartsWith("/") {
+				state = whatwgSpecialAuthorityIgnoreSlashes
+				p.pointer++
+			} else {
+				state = whatwgRelative
+				p.pointer--
+			}
+		case whatwgPathOrAuthority:
+			if c == '/' {
+				state = whatwgAuthority
+			} else {
+				state = whatwgPath
+				p.pointer--
+			}
+		case whatwgRelative:
+			u.scheme = base.scheme
+			if c == '/' || (u.isSpecial() && c == '\\') {
+				state = whatwgRelativeSlash
+				break
+			}
+			u.username, u.password = base.username, base.password
+			u.hasHost, u.host, u.port = base.hasHost, base.host, base.port
+			u.path = append([]string{}, base.path...)
+			u.hasQuery, u.query = base.hasQuery, base.query
+			switch c {
+			case '?':
+				u.hasQuery, u.query = true, ""
+				state = whatwgQuery
+			case '#':
+				u.hasFragment = true
+				state = whatwgFragment
+			case whatwgEOF:
+			default:
+				u.hasQuery, u.query = false, ""
+				u.shortenPath()
+				state = whatwgPath
+				p.pointer--
+			}
+		case whatwgRelativeSlash:
+			switch {
+			case u.isSpecial() && (c == '/' || c == '\\'):
+				state = whatwgSpecialAuthorityIgnoreSlashes
+			case c == '/':
+				state = whatwgAuthority
+			default:
+				u.username, u.password = base.username, base.password
+				u.hasHost, u.host, u.port = base.hasHost, base.host, base.port
+				state = whatwgPath
+				p.pointer--
+			}
+		case whatwgSpecialAuthoritySlashes:
+			if c == '/' && p.remainingStartsWith("/") {
+				p.pointer++
+			} else {
+				p.pointer--
+			}
+			state = whatwgSpecialAuthorityIgnoreSlashes
+		case whatwgSpecialAuthorityIgnoreSlashes:
+			if c != '/' && c != '\\' {
+				state = whatwgAuthority
+				p.pointer--
+			}
+		case whatwgAuthority:
+			switch {
+			case c == '@':
+				if atSignSeen {
+					buffer = append([]rune("%40"), buffer...)
+				}
+				atSignSeen = true
+				var username, password strings.Builder
+				for _, r := range buffer {
+					if r == ':' && !passwordTokenSeen {
+						passwordTokenSeen = true
+						continue
+					}
+					if passwordTokenSeen {
+						whatwgEncode(&password, r, isWHATWGUserInfoEncoded)
+					} else {
+						whatwgEncode(&username, r, isWHATWGUserInfoEncoded)
+					}
+				}
+				u.username += username.String()
+				u.password += password.String()
+				buffer = buffer[:0]
+			case c == whatwgEOF || c == '/' || c == '?' || c == '#' || (u.isSpecial() && c == '\\'):
+				if atSignSeen && len(buffer) == 0 {
+					return errors.New("missing host after user information")
+				}
+				p.pointer -= len(buffer) + 1
+				buffer = buffer[:0]
+				state = whatwgHost
+			default:
+				buffer = append(buffer, c)
+			}
+		case whatwgHost:
+			switch {
+			case c == ':' && !insideBrackets:
+				if len(buffer) == 0 {
+					return errors.New("missing host before port")
+				}
+				host, err := parseWHATWGHost(string(buffer), !u.isSpecial())
+				if err != nil {
+					return err
+				}
+				u.hasHost, u.host = true, host
+				buffer = buffer[:0]
+				state = whatwgPort
+			case c == whatwgEOF || c == '/' || c == '?' || c == '#' || (u.isSpecial() && c == '\\'):
+				p.pointer--
+				if u.isSpecial() && len(buffer) == 0 {
+					return errors.New("missing host")
+				}
+				host, err := parseWHATWGHost(string(buffer), !u.isSpecial())
+				if err != nil {
+					return err
+				}
+				u.hasHost, u.host = true, host
+				buffer = buffer[:0]
+				state = whatwgPathStart
+			default:
+				if c == '[' {
+					insideBrackets = true
+				} else if c == ']' {
+					insideBrackets = false
+				}
+				buffer = append(buffer, c)
+			}
+		case whatwgPort:
+			switch {
+			case c >= '0' && c <= '9':
+				buffer = append(buffer, c)
+			case c == whatwgEOF || c == '/' || c == '?' || c == '#' || (u.isSpecial() && c == '\\'):
+				if len(buffer) != 0 {
+					port := 0
+					for _, digit := range buffer {
+						port = port*10 + int(digit-'0')
+						if port > 65535 {
+							return fmt.Errorf("port out of range: %s", string(buffer))
+						}
+					}
+					if defaultPort, ok := whatwgSpecialSchemes[u.scheme]; ok && defaultPort == port {
+						u.port = nil
+					} else {
+						p16 := uint16(port)
+						u.port = &p16
+					}
+					buffer = buffer[:0]
+				}
+				state = whatwgPathStart
+				p.pointer--
+			default:
+				return fmt.Errorf("invalid character in port: %c", c)
+			}
+		case whatwgFile:
+			u.scheme = "file"
+			u.hasHost, u.host = true, ""
+			if c == '/' || c == '\\' {
+				state = whatwgFileSlash
+				break
+			}
+			if base != nil && base.scheme == "file" {
+				u.host = base.host
+				u.path = append([]string{}, base.path...)
+				u.hasQuery, u.query = base.hasQuery, base.query
+				switch c {
+				case '?':
+					u.hasQuery, u.query = true, ""
+					state = whatwgQuery
+				case '#':
+					u.hasFragment = true
+					state = whatwgFragment
+				case whatwgEOF:
+				default:
+					u.hasQuery, u.query = false, ""
+					if !startsWithWindowsDriveLetter(p.remainingFrom(p.pointer)) {
+						u.shortenPath()
+					} else {
+						u.path = nil
+					}
+					state = whatwgPath
+					p.pointer--
+				}
+				break
+			}
+			state = whatwgPath
+			p.pointer--
+		case whatwgFileSlash:
+			if c == '/' || c == '\\' {
+				state = whatwgFileHost
+				break
+			}
+			if base != nil && base.scheme == "file" {
+				u.host = base.host
+				if !startsWithWindowsDriveLetter(p.remainingFrom(p.pointer)) &&
+					len(base.path) > 0 && isNormalizedWindowsDriveLetter(base.path[0]) {
+					u.path = append(u.path, base.path[0])
+				}
+			}
+			state = whatwgPath
+			p.pointer--
+		case whatwgFileHost:
+			if c != whatwgEOF && c != '/' && c != '\\' && c != '?' && c != '#' {
+				buffer = append(buffer, c)
+				break
+			}
+			p.pointer--
+			switch {
+			case isWindowsDriveLetter(string(buffer)):
+				// The buffer is kept and becomes the first path segment.
+				state = whatwgPath
+			case len(buffer) == 0:
+				u.host = ""
+				state = whatwgPathStart
+			default:
+				host, err := parseWHATWGHost(string(buffer), false)
+				if err != nil {
+					return err
+				}
+				if host == "localhost" {
+					host = ""
+				}
+				u.host = host
+				buffer = buffer[:0]
+				state = whatwgPathStart
+			}
+		case whatwgPathStart:
+			switch {
+			case u.isSpecial():
+				state = whatwgPath
+				if c != '/' && c != '\\' {
+					p.pointer--
+				}
+			case c == '?':
+				u.hasQuery, u.query = true, ""
+				state = whatwgQuery
+			case c == '#':
+				u.hasFragment = true
+				state = whatwgFragment
+			case c != whatwgEOF:
+				state = whatwgPath
+				if c != '/' {
+					p.pointer--
+				}
+			}
+		case whatwgPath:
+			slash := c == '/' || (u.isSpecial() && c == '\\')
+			if c != whatwgEOF && !slash && c != '?' && c != '#' {
+				var b strings.Builder
+				whatwgEncode(&b, c, isWHATWGPathEncoded)
+				buffer = append(buffer, []rune(b.String())...)
+				break
+			}
+			segment := string(buffer)
+			switch {
+			case isDoubleDotSegment(segment):
+				u.shortenPath()
+				if !slash {
+					u.path = append(u.path, "")
+				}
+			case isSingleDotSegment(segment):
+				if !slash {
+					u.path = append(u.path, "")
+				}
+			default:
+				if u.scheme == "file" && len(u.path) == 0 && isWindowsDriveLetter(segment) {
+					segment = segment[:1] + ":"
+				}
+				u.path = append(u.path, segment)
+			}
+			buffer = buffer[:0]
+			switch c {
+			case '?':
+				u.hasQuery, u.query = true, ""
+				state = whatwgQuery
+			case '#':
+				u.hasFragment = true
+				state = whatwgFragment
+			}
+		case whatwgOpaquePath:
+			switch c {
+			case '?':
+				u.hasQuery, u.query = true, ""
+				state = whatwgQuery
+			case '#':
+				u.hasFragment = true
+				state = whatwgFragment
+			case whatwgEOF:
+			default:
+				var b strings.Builder
+				whatwgEncode(&b, c, isWHATWGC0ControlEncoded)
+				u.opaque += b.String()
+			}
+		case whatwgQuery:
+			if c != '#' && c != whatwgEOF {
+				buffer = append(buffer, c)
+				break
+			}
+			encoded := isWHATWGQueryEncoded
+			if u.isSpecial() {
+				encoded = isWHATWGSpecialQueryEncoded
+			}
+			var b strings.Builder
+			for _, r := range buffer {
+				whatwgEncode(&b, r, encoded)
+			}
+			u.query += b.String()
+			buffer = buffer[:0]
+			if c == '#' {
+				u.hasFragment = true
+				state = whatwgFragment
+			}
+		case whatwgFragment:
+			if c != whatwgEOF {
+				var b strings.Builder
+				whatwgEncode(&b, c, isWHATWGFragmentEncoded)
+				u.fragment += b.String()
+			}
+		}
+		if p.pointer >= len(p.input) {
+			return nil
+		}
+	}
+}
+
+func toLowerASCII(c rune) rune {
+	if c >= 'A' && c <= 'Z' {
+		return c + 'a' - 'A'
+	}
+	return c
+}
+
+// isWindowsDriveLetter checks for an ASCII letter followed by a colon or a vertical bar.
+func isWindowsDriveLetter(s string) bool {
+	return len(s) == 2 && isALPHA(s[0]) && (s[1] == ':' || s[1] == '|')
+}
+
+func isNormalizedWindowsDriveLetter(s string) bool {
+	return isWindowsDriveLetter(s) && s[1] == ':'
+}
+
+func startsWithWindowsDriveLetter(r []rune) bool {
+	if len(r) < 2 || r[0] >= utf8.RuneSelf || !isWindowsDriveLetter(string(r[:2])) {
+		return false
+	}
+	return len(r) == 2 || r[2] == '/' || r[2] == '\\' || r[2] == '?' || r[2] == '#'
+}
+
+func isSingleDotSegment(s string) bool {
+	return s == "." || strings.EqualFold(s, "%2e")
+}
+
+func isDoubleDotSegment(s string) bool {
+	switch strings.ToLower(s) {
+	case "..", ".%2e", "%2e.", "%2e%2e":
+		return true
+	}
+	return false
+}
+
+//endregion
+
+//region Percent-encoding
+
+// whatwgEncode writes the code point to b, percent-encoding its UTF-8 bytes if it is in the encode set.
+func whatwgEncode(b *strings.Builder, r rune, encoded func(r rune) bool) {
+	if !encoded(r) {
+		b.WriteRune(r)
+		return
+	}
+	var buf [utf8.UTFMax]byte
+	for _, c := range buf[:utf8.EncodeRune(buf[:], r)] {
+		b.WriteByte('%')
+		b.WriteByte(upperHex[c>>4])
+		b.WriteByte(upperHex[c&15])
+	}
+}
+
+func isWHATWGC0ControlEncoded(r rune) bool {
+	return r < 0x20 || r > 0x7e
+}
+
+func isWHATWGFragmentEncoded(r rune) bool {
+	return isWHATWGC0ControlEncoded(r) || r == ' ' || r == '"' || r == '<' || r == '>' || r == '`'
+}
+
+func isWHATWGQueryEncoded(r rune) bool {
+	return isWHATWGC0ControlEncoded(r) || r == ' ' || r == '"' || r == '#' || r == '<' || r == '>'
+}
+
+func isWHATWGSpecialQueryEncoded(r rune) bool {
+	return isWHATWGQueryEncoded(r) || r == '\''
+}
+
+func isWHATWGPathEncoded(r rune) bool {
+	return isWHATWGQueryEncoded(r) || r == '?' || r == '`' || r == '{' || r == '}'
+}
+
+func isWHATWGUserInfoEncoded(r rune) bool {
+	return isWHATWGPathEncoded(r) || r == '/' || r == ':' || r == ';' || r == '=' || r == '@' ||
+		(r >= '[' && r <= '^') || r == '|'
+}
+
+//endregion
+
+//region Hosts
+
+// whatwgIDNAProfile implements domain to ASCII with beStrict set to false.
+//
+// See https://url.spec.whatwg.org/#concept-domain-to-ascii for details.
+var whatwgIDNAProfile = idna.New(
+	idna.MapForLookup(),
+	idna.BidiRule(),
+	idna.CheckJoiners(true),
+	idna.CheckHyphens(false),
+	idna.StrictDomainName(false),
+	idna.VerifyDNSLength(false),
+	idna.Transitional(false),
+)
+
+func isForbiddenHostCodePoint(r rune) bool {
+	switch r {
+	case 0, '\t', '\n', '\r', ' ', '#', '/', ':', '<', '>', '?', '@', '[', '\\', ']', '^', '|':
+		return true
+	}
+	return false
+}
+
+func isForbiddenDomainCodePoint(r rune) bool {
+	return isForbiddenHostCodePoint(r) || r <= 0x1f || r == '%' || r == 0x7f
+}
+
+// parseWHATWGHost parses and serializes a host. Hosts of non-special URLs are opaque and only percent-encoded.
+func parseWHATWGHost(input string, isOpaque bool) (string, error) {
+	if strings.HasPrefix(input, "[") {
+		if !strings.HasSuffix(input, "]") {
+			return "", fmt.Errorf("unterminated IPv6 address: %s", input)
+		}
+		address, err := parseWHATWGIPv6([]rune(input[1 : len(input)-1]))
+		if err != nil {
+			return "", err
+		}
+		return "[" + serializeWHATWGIPv6(address) + "]", nil
+	}
+	if isOpaque {
+		var b strings.Builder
+		for _, r := range input {
+			if isForbiddenHostCodePoint(r) {
+				return "", fmt.Errorf("invalid character in host %s: %c", input, r)
+			}
+			whatwgEncode(&b, r, isWHATWGC0ControlEncoded)
+		}
+		return b.String(), nil
+	}
+	domain := strings.ToValidUTF8(percentDecode(input), "�")
+	asciiDomain, err := whatwgDomainToASCII(domain)
+	if err != nil {
+		return "", err
+	}
+	for _, r := range asciiDomain {
+		if isForbiddenDomainCodePoint(r) {
+			return "", fmt.Errorf("invalid character in host %s: %c", asciiDomain, r)
+		}
+	}
+	if endsInANumber(asciiDomain) {
+		return parseWHATWGIPv4(asciiDomain)
+	}
+	return asciiDomain, nil
+}
+
+func whatwgDomainToASCII(domain string) (string, error) {
+	result := strings.ToLower(domain)
+	needsIDNA := false
+	for _, r := range domain {
+		if r >= utf8.RuneSelf {
+			needsIDNA = true
+			break
+		}
+	}
+	for _, label := range strings.Split(result, ".") {
+		if strings.HasPrefix(label, "xn--") {
+			needsIDNA = true
+		}
+	}
+	if needsIDNA {
+		var err error
+		result, err = whatwgIDNAProfile.ToASCII(domain)
+		if err != nil {
+			return "", fmt.Errorf("invalid internationalized host %s (%w)", domain, err)
+		}
+	}
+	if result == "" {
+		return "", errors.New("empty host")
+	}
+	return result, nil
+}
+
+// endsInANumber checks if the last label of the domain is a number, in which case the domain is an IPv4 address.
+func endsInANumber(domain string) bool {
+	parts := strings.Split(domain, ".")
+	if parts[len(parts)-1] == "" {
+		if len(parts) == 1 {
+			return false
+		}
+		parts = parts[:len(parts)-1]
+	}
+	last := parts[len(parts)-1]
+	if last != "" && strings.Trim(last, "0123456789") == "" {
+		return true
+	}
+	_, err := parseWHATWGIPv4Number(last)
+	return err == nil
+}
+
+// parseWHATWGIPv4Number parses a decimal, octal (leading 0) or hexadecimal (leading 0x) number. Values that do not fit
+// into 64 bits are capped, since they are rejected as out of range anyway.
+func parseWHATWGIPv4Number(input string) (uint64, error) {
+	if input == "" {
+		return 0, errors.New("empty IPv4 number")
+	}
+	radix := uint64(10)
+	switch {
+	case strings.HasPrefix(input, "0x") || strings.HasPrefix(input, "0X"):
+		input = input[2:]
+		radix = 16
+	case len(input) > 1 && input[0] == '0':
+		input = input[1:]
+		radix = 8
+	}
+	var result uint64
+	for i := 0; i < len(input); i++ {
+		c := input[i]
+		if !isHEXDIG(c) || uint64(unhex(c)) >= radix || (radix != 16 && !isDIGIT(c)) {
+			return 0, fmt.Errorf("invalid IPv4 number: %s", input)
+		}
+		if result < 1<<40 {
+			result = result*radix + uint64(unhex(c))
+		}
+	}
+	return result, nil
+}
+
+func parseWHATWGIPv4(input string) (string, error) {
+	parts := strings.Split(input, ".")
+	if parts[len(parts)-1] == "" && len(parts) > 1 {
+		parts = parts[:len(parts)-1]
+	}
+	if len(parts) > 4 {
+		return "", fmt.Errorf("too many parts in IPv4 address: %s", input)
+	}
+	numbers := make([]uint64, len(parts))
+	for i, part := range parts {
+		n, err := parseWHATWGIPv4Number(part)
+		if err != nil {
+			return "", fmt.Errorf("invalid IPv4 address %s (%w)", input, err)
+		}
+		if n > 255 && i != len(parts)-1 {
+			return "", fmt.Errorf("IPv4 address part out of range: %s", input)
+		}
+		numbers[i] = n
+	}
+	ipv4 := numbers[len(numbers)-1]
+	if ipv4 >= 1<<(8*(5-len(numbers))) {
+		return "", fmt.Errorf("IPv4 address out of range: %s", input)
+	}
+	for i, n := range numbers[:len(numbers)-1] {
+		ipv4 += n << (8 * (3 - i))
+	}
+	return fmt.Sprintf("%d.%d.%d.%d", ipv4>>24, ipv4>>16&0xff, ipv4>>8&0xff, ipv4&0xff), nil
+}
+
+func parseWHATWGIPv6(input []rune) ([8]uint16, error) {
+	var address [8]uint16
+	invalid := fmt.Errorf("invalid IPv6 address: %s", string(input))
+	at := func(i int) rune {
+		if i >= len(input) {
+			return whatwgEOF
+		}
+		return input[i]
+	}
+	isHex := func(r rune) bool {
+		return r >= 0 && r < utf8.RuneSelf && isHEXDIG(byte(r))
+	}
+	pieceIndex, compress, pointer := 0, -1, 0
+	if at(pointer) == ':' {
+		if at(pointer+1) != ':' {
+			return address, invalid
+		}
+		pointer += 2
+		pieceIndex++
+		compress = pieceIndex
+	}
+	for at(pointer) != whatwgEOF {
+		if pieceIndex == 8 {
+			return address, invalid
+		}
+		if at(pointer) == ':' {
+			if compress != -1 {
+				return address, invalid
+			}
+			pointer++
+			pieceIndex++
+			compress = pieceIndex
+			continue
+		}
+		value, length := 0, 0
+		for length < 4 && isHex(at(pointer)) {
+			value = value*16 + int(unhex(byte(at(pointer))))
+			pointer++
+			length++
+		}
+		if at(pointer) == '.' {
+			if length == 0 || pieceIndex > 6 {
+				return address, invalid
+			}
+			pointer -= length
+			numbersSeen := 0
+			for at(pointer) != whatwgEOF {
+				ipv4Piece := -1
+				if numbersSeen > 0 {
+					if at(pointer) != '.' || numbersSeen >= 4 {
+						return address, invalid
+					}
+					pointer++
+				}
+				if c := at(pointer); c < '0' || c > '9' {
+					return address, invalid
+				}
+				for c := at(pointer); c >= '0' && c <= '9'; c = at(pointer) {
+					number := int(c - '0')
+					switch ipv4Piece {
+					case -1:
+						ipv4Piece = number
+					case 0:
+						return address, invalid
+					default:
+						ipv4Piece = ipv4Piece*10 + number
+					}
+					if ipv4Piece > 255 {
+						return address, invalid
+					}
+					pointer++
+				}
+				address[pieceIndex] = address[pieceIndex]*0x100 + uint16(ipv4Piece)
+				numbersSeen++
+				if numbersSeen == 2 || numbersSeen == 4 {
+					pieceIndex++
+				}
+			}
+			if numbersSeen != 4 {
+				return address, invalid
+			}
+			break
+		} else if at(pointer) == ':' {
+			pointer++
+			if at(pointer) == whatwgEOF {
+				return address, invalid
+			}
+		} else if at(pointer) != whatwgEOF {
+			return address, invalid
+		}
+		address[pieceIndex] = uint16(value)
+		pieceIndex++
+	}
+	if compress != -1 {
+		swaps := pieceIndex - compress
+		pieceIndex = 7
+		for pieceIndex != 0 && swaps > 0 {
+			address[pieceIndex], address[compress+swaps-1] = address[compress+swaps-1], address[pieceIndex]
+			pieceIndex--
+			swaps--
+		}
+	} else if pieceIndex != 8 {
+		return address, invalid
+	}
+	return address, nil
+}
+
+// serializeWHATWGIPv6 serializes the address in lowercase hexadecimal, compressing the first longest run of at least
+// two zero pieces. Unlike netip, embedded IPv4 addresses are serialized in hexadecimal as well.
+func serializeWHATWGIPv6(address [8]uint16) string {
+	compress, longest := -1, 1
+	for i := 0; i < 8; {
+		if address[i] != 0 {
+			i++
+			continue
+		}
+		start := i
+		for i < 8 && address[i] == 0 {
+			i++
+		}
+		if i-start > longest {
+			compress, longest = start, i-start
+		}
+	}
+	var b strings.Builder
+	for i := 0; i < 8; i++ {
+		if i == compress {
+			if i == 0 {
+				b.WriteString("::")
+			} else {
+				b.WriteByte(':')
+			}
+			i += longest - 1
+			continue
+		}
+		b.WriteString(strconv.FormatUint(uint64(address[i]), 16))
+		if i != 7 {
+			b.WriteByte(':')
+		}
+	}
+	return b.String()
+}
+
+//endregion
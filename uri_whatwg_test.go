@@ -0,0 +1,121 @@
+package gsr7_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"go.debugged.it/gsr7"
+)
+
+//region Examples
+
+func ExampleParseURIWHATWG() {
+	fmt.Println(gsr7.ParseURIWHATWG(" HTTP://EXAMPLE.com:80\\a\\..\\b\t/c?d e#f", nil))
+	fmt.Println(gsr7.ParseURIWHATWG("http:/example.com", nil))
+	fmt.Println(gsr7.ParseURIWHATWG("http://%65xample.com/", nil))
+	fmt.Println(gsr7.ParseURIWHATWG("http://0x7f.1/", nil))
+	fmt.Println(gsr7.ParseURIWHATWG("../d", gsr7.ParseURIWHATWG("http://example.com/a/b/c", nil)))
+	// Output: http://example.com/b/c?d%20e#f
+	// http://example.com/
+	// http://example.com/
+	// http://127.0.0.1/
+	// http://example.com/a/d
+}
+
+//endregion
+
+//region Tests
+
+// whatwgURLTestCase is a test case in the format of the urltestdata.json file of the web-platform-tests project.
+type whatwgURLTestCase struct {
+	Input    string  `json:"input"`
+	Base     *string `json:"base"`
+	Href     string  `json:"href"`
+	Failure  bool    `json:"failure"`
+	Hostname string  `json:"hostname"`
+	Port     string  `json:"port"`
+	Search   string  `json:"search"`
+	Hash     string  `json:"hash"`
+}
+
+func loadWHATWGURLTests(t *testing.T) []whatwgURLTestCase {
+	data, err := os.ReadFile(filepath.Join("testdata", "whatwg", "urltestdata.json"))
+	if err != nil {
+		t.Fatalf("failed to read urltestdata.json (%v)", err)
+	}
+	// The file contains comments as plain strings between the test cases.
+	var entries []json.RawMessage
+	if err := json.Unmarshal(data, &entries); err != nil {
+		t.Fatalf("failed to parse urltestdata.json (%v)", err)
+	}
+	var testCases []whatwgURLTestCase
+	for _, entry := range entries {
+		if entry[0] != '{' {
+			continue
+		}
+		var testCase whatwgURLTestCase
+		if err := json.Unmarshal(entry, &testCase); err != nil {
+			t.Fatalf("failed to parse test case %s (%v)", entry, err)
+		}
+		testCases = append(testCases, testCase)
+	}
+	return testCases
+}
+
+func TestParseURIWHATWG(t *testing.T) {
+	for i, testCase := range loadWHATWGURLTests(t) {
+		testCase := testCase
+		name := strconv.Itoa(i) + " " + testCase.Input
+		if testCase.Base != nil {
+			name += " against " + *testCase.Base
+		}
+		t.Run(
+			name, func(t *testing.T) {
+				var base gsr7.URI
+				if testCase.Base != nil {
+					var err error
+					base, err = gsr7.ParseURIWHATWGE(*testCase.Base, nil)
+					if err != nil {
+						if testCase.Failure {
+							return
+						}
+						t.Fatalf("failed to parse base URL %s (%v)", *testCase.Base, err)
+					}
+				}
+				uri, err := gsr7.ParseURIWHATWGE(testCase.Input, base)
+				if testCase.Failure {
+					if err == nil {
+						t.Fatalf("parsing did not return an error (result: %s)", uri)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("failed to parse URL (%v)", err)
+				}
+				assertEquals(t, uri.String(), testCase.Href, "incorrect href")
+				assertEquals(t, uri.GetHost(), testCase.Hostname, "incorrect hostname")
+				port := ""
+				if uri.GetPort() != nil {
+					port = strconv.Itoa(int(*uri.GetPort()))
+				}
+				assertEquals(t, port, testCase.Port, "incorrect port")
+				search := ""
+				if uri.GetQuery() != "" {
+					search = "?" + uri.GetQuery()
+				}
+				assertEquals(t, search, testCase.Search, "incorrect search")
+				hash := ""
+				if uri.GetFragment() != "" {
+					hash = "#" + uri.GetFragment()
+				}
+				assertEquals(t, hash, testCase.Hash, "incorrect hash")
+			},
+		)
+	}
+}
+
+//endregion
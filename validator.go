@@ -1,6 +1,13 @@
 package gsr7
 
-import "fmt"
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+
+	"go.debugged.it/gsr7/method"
+	"go.debugged.it/gsr7/publicsuffix"
+)
 
 type validator func() error
 
@@ -71,6 +78,9 @@ func validateCookieDomain(domain string) validator {
 				return fmt.Errorf("invalid character in domain name position %d (%d)", i, letter)
 			}
 		}
+		if publicsuffix.IsListedPublicSuffix(strings.TrimPrefix(domain, ".")) {
+			return fmt.Errorf("the domain %s is a public suffix", domain)
+		}
 		return nil
 	}
 }
@@ -103,3 +113,258 @@ func validateExtensions(extensions []string) validator {
 		return nil
 	}
 }
+
+func validateURIScheme(scheme string) validator {
+	return func() error {
+		for i := 0; i < len(scheme); i++ {
+			if !isSchemeChar(scheme[i]) || (i == 0 && !isALPHA(scheme[i])) {
+				return fmt.Errorf("invalid character in URI scheme position %d (%d)", i, scheme[i])
+			}
+		}
+		return nil
+	}
+}
+
+func validateURIComponent(component string, value string, allowed uriCharset) validator {
+	return func() error {
+		return checkPercentEncoded(component, value, allowed)
+	}
+}
+
+func validateURIHost(host string) validator {
+	return func() error {
+		if strings.HasPrefix(host, "[") {
+			if !strings.HasSuffix(host, "]") {
+				return fmt.Errorf("unterminated IP literal in URI host: %s", host)
+			}
+			return validateIPLiteral(host[1 : len(host)-1])
+		}
+		return checkPercentEncoded("host", host, isRegNameChar)
+	}
+}
+
+func validateIPLiteral(literal string) error {
+	if strings.HasPrefix(literal, "v") || strings.HasPrefix(literal, "V") {
+		// IPvFuture = "v" 1*HEXDIG "." 1*( unreserved / sub-delims / ":" )
+		dot := strings.IndexByte(literal, '.')
+		if dot < 2 || dot == len(literal)-1 {
+			return fmt.Errorf("invalid IPvFuture literal in URI host: %s", literal)
+		}
+		for i := 1; i < dot; i++ {
+			if !isHEXDIG(literal[i]) {
+				return fmt.Errorf("invalid IPvFuture version in URI host: %s", literal)
+			}
+		}
+		for i := dot + 1; i < len(literal); i++ {
+			if !isUserInfoChar(literal[i]) {
+				return fmt.Errorf("invalid character in IPvFuture literal position %d (%d)", i, literal[i])
+			}
+		}
+		return nil
+	}
+	// A zone identifier is separated by an encoded percent sign, see https://datatracker.ietf.org/doc/html/rfc6874
+	if i := strings.Index(literal, "%25"); i >= 0 {
+		zone := literal[i+3:]
+		if zone == "" {
+			return fmt.Errorf("empty zone identifier in URI host: %s", literal)
+		}
+		if err := checkPercentEncoded("zone identifier", zone, isUnreserved); err != nil {
+			return err
+		}
+		literal = literal[:i]
+	}
+	addr, err := netip.ParseAddr(literal)
+	if err != nil || !addr.Is6() || addr.Zone() != "" {
+		return fmt.Errorf("invalid IPv6 literal in URI host: %s", literal)
+	}
+	return nil
+}
+
+// validateURIPath checks the path restrictions from RFC 3986 section 3.3 that depend on the other components.
+func validateURIPath(scheme string, hasAuthority bool, path string) validator {
+	return func() error {
+		if hasAuthority {
+			if path != "" && path[0] != '/' {
+				return fmt.Errorf("the path of a URI with an authority must be empty or start with a slash: %s", path)
+			}
+			return nil
+		}
+		if strings.HasPrefix(path, "//") {
+			return fmt.Errorf("the path of a URI without an authority must not start with two slashes: %s", path)
+		}
+		if scheme == "" {
+			segment := path
+			if i := strings.IndexByte(segment, '/'); i >= 0 {
+				segment = segment[:i]
+			}
+			if strings.IndexByte(segment, ':') >= 0 {
+				return fmt.Errorf("the first segment of a relative path must not contain a colon: %s", path)
+			}
+		}
+		return nil
+	}
+}
+
+func validateDataURIMediaType(mediaType string) validator {
+	return func() error {
+		for i, letter := range mediaType {
+			if letter < 32 || letter == 127 || letter == ',' {
+				return fmt.Errorf("invalid character in data URI media type position %d (%d)", i, letter)
+			}
+		}
+		return nil
+	}
+}
+
+// isTokenChar checks for the tchar characters in RFC 9110 section 5.6.2.
+func isTokenChar(c byte) bool {
+	switch c {
+	case '!', '#', '$', '%', '&', '\'', '*', '+', '-', '.', '^', '_', '`', '|', '~':
+		return true
+	}
+	return isALPHA(c) || isDIGIT(c)
+}
+
+func validateHeaderName(name string) validator {
+	return func() error {
+		if name == "" {
+			return fmt.Errorf("empty header name")
+		}
+		for i := 0; i < len(name); i++ {
+			if !isTokenChar(name[i]) {
+				return fmt.Errorf("invalid character in header name %q position %d (%d)", name, i, name[i])
+			}
+		}
+		return nil
+	}
+}
+
+func validateHeaderValues(values []string) validator {
+	return func() error {
+		for _, value := range values {
+			if i := strings.IndexAny(value, "\r\n\x00"); i >= 0 {
+				return fmt.Errorf("invalid character in header value position %d (%d)", i, value[i])
+			}
+		}
+		return nil
+	}
+}
+
+func validateRequestTarget(requestTarget string) validator {
+	return func() error {
+		if requestTarget == "" {
+			return fmt.Errorf("empty request target")
+		}
+		for i := 0; i < len(requestTarget); i++ {
+			if c := requestTarget[i]; c <= ' ' || c == 0x7f {
+				return fmt.Errorf("invalid character in request target position %d (%d)", i, c)
+			}
+		}
+		return nil
+	}
+}
+
+func validateRequestURI(uri URI) validator {
+	return func() error {
+		if uri == nil {
+			return fmt.Errorf("the request URI must not be nil")
+		}
+		return nil
+	}
+}
+
+func validateMethod(name string) validator {
+	return func() error {
+		return method.Validate(name)
+	}
+}
+
+func validateStatusCode(code uint16) validator {
+	return func() error {
+		if code < 100 || code > 599 {
+			return fmt.Errorf("invalid status code: %d", code)
+		}
+		return nil
+	}
+}
+
+func validateReasonPhrase(reasonPhrase string) validator {
+	return func() error {
+		for i := 0; i < len(reasonPhrase); i++ {
+			if c := reasonPhrase[i]; (c < ' ' && c != '\t') || c == 0x7f {
+				return fmt.Errorf("invalid character in reason phrase position %d (%d)", i, c)
+			}
+		}
+		return nil
+	}
+}
+
+func validateChunkExtensions(extensions []ChunkExtension) validator {
+	return func() error {
+		for _, extension := range extensions {
+			if err := validateHeaderName(extension.Name)(); err != nil {
+				return fmt.Errorf("invalid chunk extension name %q", extension.Name)
+			}
+			for i := 0; i < len(extension.Value); i++ {
+				if c := extension.Value[i]; (c < ' ' && c != '\t') || c == 0x7f {
+					return fmt.Errorf("invalid character in chunk extension value position %d (%d)", i, c)
+				}
+			}
+		}
+		return nil
+	}
+}
+
+// forbiddenTrailers contains the fields that must not be sent in a trailer section, since they are needed for framing,
+// routing, authentication, request modifiers, response control data or processing the content. See RFC 9110 section
+// 6.5.1.
+var forbiddenTrailers = map[string]struct{}{
+	"age":                       {},
+	"authentication-info":       {},
+	"authorization":             {},
+	"cache-control":             {},
+	"connection":                {},
+	"content-encoding":          {},
+	"content-length":            {},
+	"content-range":             {},
+	"content-type":              {},
+	"cookie":                    {},
+	"date":                      {},
+	"expect":                    {},
+	"expires":                   {},
+	"host":                      {},
+	"if-match":                  {},
+	"if-modified-since":         {},
+	"if-none-match":             {},
+	"if-range":                  {},
+	"if-unmodified-since":       {},
+	"keep-alive":                {},
+	"location":                  {},
+	"max-forwards":              {},
+	"pragma":                    {},
+	"proxy-authenticate":        {},
+	"proxy-authentication-info": {},
+	"proxy-authorization":       {},
+	"proxy-connection":          {},
+	"range":                     {},
+	"retry-after":               {},
+	"set-cookie":                {},
+	"te":                        {},
+	"trailer":                   {},
+	"transfer-encoding":         {},
+	"upgrade":                   {},
+	"vary":                      {},
+	"www-authenticate":          {},
+}
+
+func validateTrailerName(name string) validator {
+	return func() error {
+		if err := validateHeaderName(name)(); err != nil {
+			return err
+		}
+		if _, ok := forbiddenTrailers[headerKey(name)]; ok {
+			return fmt.Errorf("the %s field is not allowed in trailers", name)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,582 @@
+package gsr7
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+//region Constants
+
+// Errors returned by the HTTP/1.x parser. The parser wraps them in a ParseError, so they must be checked with
+// errors.Is.
+var (
+	// ErrLineTooLong indicates that the start line, a header line or a chunk size line exceeds
+	// ParseLimits.MaxLineLength.
+	ErrLineTooLong = errors.New("line too long")
+	// ErrTooManyHeaders indicates that the header or trailer section contains more fields than
+	// ParseLimits.MaxHeaderCount.
+	ErrTooManyHeaders = errors.New("too many header fields")
+	// ErrBodyTooLarge indicates that the body exceeds ParseLimits.MaxBodySize.
+	ErrBodyTooLarge = errors.New("body too large")
+	// ErrMalformedStartLine indicates an invalid request line or status line.
+	ErrMalformedStartLine = errors.New("malformed start line")
+	// ErrUnsupportedVersion indicates a HTTP version other than 1.x.
+	ErrUnsupportedVersion = errors.New("unsupported HTTP version")
+	// ErrMalformedHeader indicates an invalid header or trailer field, or a missing or invalid Host header.
+	ErrMalformedHeader = errors.New("malformed header field")
+	// ErrInvalidFraming indicates that the length of the body cannot be determined reliably, for example because of
+	// an invalid Content-Length or conflicting Content-Length and Transfer-Encoding headers. Such messages are a
+	// common vector for request smuggling.
+	ErrInvalidFraming = errors.New("invalid message framing")
+	// ErrMalformedChunk indicates an invalid chunk in a body with chunked transfer coding.
+	ErrMalformedChunk = errors.New("malformed chunk")
+)
+
+const (
+	defaultMaxLineLength  = 8192
+	defaultMaxHeaderCount = 100
+	defaultMaxBodySize    = 10 * 1024 * 1024
+)
+
+//endregion
+
+//region Interface
+
+// ParseLimits restricts the size of the messages accepted by ReadServerRequest and ReadClientResponse. Fields left
+// at zero use the value from DefaultParseLimits, negative values disable the limit.
+type ParseLimits struct {
+	// MaxLineLength is the maximum length of the start line, a header line or a chunk size line in bytes, excluding
+	// the line terminator.
+	MaxLineLength int
+	// MaxHeaderCount is the maximum number of field lines in the header section, and separately in the trailer
+	// section.
+	MaxHeaderCount int
+	// MaxBodySize is the maximum size of the body in bytes after removing the chunked transfer coding. The names and
+	// values of chunk extensions count against it, since they are retained along with the body.
+	MaxBodySize int64
+}
+
+// DefaultParseLimits returns the default limits: lines of up to 8 KiB, 100 header fields and a body of up to 10 MiB.
+func DefaultParseLimits() ParseLimits {
+	return ParseLimits{
+		MaxLineLength:  defaultMaxLineLength,
+		MaxHeaderCount: defaultMaxHeaderCount,
+		MaxBodySize:    defaultMaxBodySize,
+	}
+}
+
+// ParseError is returned when a message cannot be parsed. Err wraps one of the Err* variables of this package with a
+// description of the problem, or io.ErrUnexpectedEOF if the input ended within the message.
+type ParseError struct {
+	// Offset is the number of bytes from the start of the message to the position of the error.
+	Offset int64
+	// Err describes the error.
+	Err error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("failed to parse HTTP message at byte offset %d (%v)", e.Offset, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// ReadServerRequest reads a HTTP/1.x request as received by a server, as described in RFC 9112. Empty lines before
+// the request line are skipped. The body is read completely, either with the length from the Content-Length header
+// or by removing the chunked transfer coding. The reader is left at the start of the next message, so it can be
+// called again for the next request on a persistent connection. The trailers of a chunked body are available from
+// GetTrailers once the body has been read.
+//
+// The URI is reconstructed from the request target and the Host header. Since the connection is not known, the
+// scheme is always http and the server parameters are empty; servers should set the scheme with WithURI as needed.
+// HTTP/1.1 requests must have exactly one valid Host header.
+//
+// If the input ends before the request line io.EOF is returned. All other errors are of the type *ParseError.
+//
+// See https://datatracker.ietf.org/doc/html/rfc9112 for details.
+func ReadServerRequest(r *bufio.Reader, limits ParseLimits) (ServerRequest, error) {
+	w := &wireReader{r: r, limits: limits.withDefaults()}
+	line, start, err := w.readStartLine()
+	if err != nil {
+		return nil, err
+	}
+	first := strings.IndexByte(line, ' ')
+	last := strings.LastIndexByte(line, ' ')
+	if first <= 0 || first == last {
+		return nil, parseErrorf(
+			start, ErrMalformedStartLine, "the request line must contain a method, a request target and a version",
+		)
+	}
+	method, target, versionString := line[:first], line[first+1:last], line[last+1:]
+	if err := validate(validateMethod(method)); err != nil {
+		return nil, parseErrorf(start, ErrMalformedStartLine, "%v", err)
+	}
+	protocolVersion, err := parseWireVersion(versionString)
+	if err != nil {
+		return nil, &ParseError{Offset: start + int64(last) + 1, Err: err}
+	}
+	requestTarget, err := ParseRequestTargetE(method, target)
+	if err != nil {
+		return nil, parseErrorf(start+int64(first)+1, ErrMalformedStartLine, "%v", err)
+	}
+
+	headersStart := w.offset
+	headers, err := w.readFields(true, false)
+	if err != nil {
+		return nil, err
+	}
+	base, err := baseURIFromHost(headers, protocolVersion)
+	if err != nil {
+		return nil, &ParseError{Offset: headersStart, Err: err}
+	}
+	body, err := w.readBody(headers, protocolVersion, true, false)
+	if err != nil {
+		return nil, err
+	}
+	return &serverRequest{
+		message: message{
+			protocolVersion: protocolVersion,
+			headers:         headers,
+		},
+		method:        method,
+		uri:           requestTarget.ResolveURI(base),
+		requestTarget: requestTarget,
+		body:          body,
+	}, nil
+}
+
+// ReadClientResponse reads a HTTP/1.x response as received by a client, as described in RFC 9112. The method of the
+// request is needed to determine whether the response has a body: responses to HEAD requests, successful responses
+// to CONNECT requests and responses with the status codes 1xx, 204 and 304 never have one. Without Content-Length and
+// chunked transfer coding, the body extends to the end of the input. The trailers of a chunked body are available from
+// GetTrailers once the body has been read.
+//
+// Interim 1xx responses are returned like final responses, so clients have to call ReadClientResponse again to
+// receive the final response.
+//
+// If the input ends before the status line io.EOF is returned. All other errors are of the type *ParseError.
+//
+// See https://datatracker.ietf.org/doc/html/rfc9112 for details.
+func ReadClientResponse(r *bufio.Reader, requestMethod string, limits ParseLimits) (ClientResponse, error) {
+	w := &wireReader{r: r, limits: limits.withDefaults()}
+	line, start, err := w.readStartLine()
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 12 || line[8] != ' ' || (len(line) > 12 && line[12] != ' ') {
+		return nil, parseErrorf(
+			start, ErrMalformedStartLine, "the status line must contain a version and a status code",
+		)
+	}
+	protocolVersion, err := parseWireVersion(line[:8])
+	if err != nil {
+		return nil, &ParseError{Offset: start, Err: err}
+	}
+	code := uint16(0)
+	for i := 9; i < 12; i++ {
+		if !isDIGIT(line[i]) {
+			return nil, parseErrorf(start+int64(i), ErrMalformedStartLine, "invalid status code %s", line[9:12])
+		}
+		code = code*10 + uint16(line[i]-'0')
+	}
+	reasonPhrase := ""
+	if len(line) > 12 {
+		reasonPhrase = line[13:]
+	}
+	s, err := newStatusLine(code, reasonPhrase)
+	if err != nil {
+		return nil, parseErrorf(start+9, ErrMalformedStartLine, "%v", err)
+	}
+
+	headers, err := w.readFields(false, false)
+	if err != nil {
+		return nil, err
+	}
+	noBody := requestMethod == "HEAD" || code < 200 || code == 204 || code == 304 ||
+		(requestMethod == "CONNECT" && code < 300)
+	body, err := w.readBody(headers, protocolVersion, false, noBody)
+	if err != nil {
+		return nil, err
+	}
+	return NewClientResponseE(protocolVersion, s.code, s.reasonPhrase, headers, body)
+}
+
+//endregion
+
+//region Implementation
+
+func (l ParseLimits) withDefaults() ParseLimits {
+	if l.MaxLineLength == 0 {
+		l.MaxLineLength = defaultMaxLineLength
+	}
+	if l.MaxHeaderCount == 0 {
+		l.MaxHeaderCount = defaultMaxHeaderCount
+	}
+	if l.MaxBodySize == 0 {
+		l.MaxBodySize = defaultMaxBodySize
+	}
+	return l
+}
+
+func parseErrorf(offset int64, kind error, format string, args ...any) *ParseError {
+	return &ParseError{
+		Offset: offset,
+		Err:    fmt.Errorf("%w: %s", kind, fmt.Sprintf(format, args...)),
+	}
+}
+
+// wireReader reads the elements of a HTTP/1.x message and keeps track of the offset within the message.
+type wireReader struct {
+	r      *bufio.Reader
+	limits ParseLimits
+	offset int64
+}
+
+// readLine reads a line terminated by CRLF or a single LF and returns it without the terminator, along with the
+// offset of the start of the line. A CR within the line is reported as kind.
+func (w *wireReader) readLine(kind error) (string, int64, error) {
+	start := w.offset
+	var line []byte
+	for {
+		chunk, err := w.r.ReadSlice('\n')
+		line = append(line, chunk...)
+		w.offset += int64(len(chunk))
+		if w.limits.MaxLineLength >= 0 && len(line) > w.limits.MaxLineLength+2 {
+			return "", start, parseErrorf(
+				start+int64(w.limits.MaxLineLength), ErrLineTooLong, "the line exceeds %d bytes", w.limits.MaxLineLength,
+			)
+		}
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		if err != nil {
+			if err == io.EOF && len(line) == 0 {
+				return "", start, io.EOF
+			}
+			return "", start, &ParseError{Offset: w.offset, Err: io.ErrUnexpectedEOF}
+		}
+		break
+	}
+	line = bytes.TrimSuffix(line[:len(line)-1], []byte{'\r'})
+	if w.limits.MaxLineLength >= 0 && len(line) > w.limits.MaxLineLength {
+		return "", start, parseErrorf(
+			start+int64(w.limits.MaxLineLength), ErrLineTooLong, "the line exceeds %d bytes", w.limits.MaxLineLength,
+		)
+	}
+	if i := bytes.IndexByte(line, '\r'); i >= 0 {
+		return "", start, parseErrorf(start+int64(i), kind, "bare CR")
+	}
+	return string(line), start, nil
+}
+
+// readCRLFLine reads a line like readLine, but rejects lines terminated by a single LF as kind.
+func (w *wireReader) readCRLFLine(kind error) (string, int64, error) {
+	line, start, err := w.readLine(kind)
+	if err == nil && w.offset-start != int64(len(line))+2 {
+		return "", start, parseErrorf(w.offset-1, kind, "bare LF")
+	}
+	return line, start, err
+}
+
+// readStartLine reads the start line, skipping empty lines before it. If the input ends before the start line,
+// io.EOF is returned.
+func (w *wireReader) readStartLine() (string, int64, error) {
+	for {
+		line, start, err := w.readLine(ErrMalformedStartLine)
+		if err != nil {
+			if err == io.EOF && start > 0 {
+				return "", start, &ParseError{Offset: start, Err: io.ErrUnexpectedEOF}
+			}
+			return "", start, err
+		}
+		if line != "" {
+			return line, start, nil
+		}
+		if w.limits.MaxLineLength >= 0 && w.offset > int64(w.limits.MaxLineLength) {
+			return "", start, parseErrorf(start, ErrLineTooLong, "too many empty lines before the start line")
+		}
+	}
+}
+
+// readFields reads a header or trailer section up to and including the empty line terminating it. Obsolete line
+// folding is rejected in requests and replaced by a space in responses. Continuation lines count against
+// MaxHeaderCount like any other field line. If requireCRLF is set, lines terminated by a single LF are rejected.
+//
+// See https://datatracker.ietf.org/doc/html/rfc9112#section-5 for details.
+func (w *wireReader) readFields(isRequest bool, requireCRLF bool) (Headers, error) {
+	headers := NewHeaders()
+	count := 0
+	// The last field is only added once it is known that no continuation lines follow.
+	lastName := ""
+	var lastValue strings.Builder
+	for {
+		readLine := w.readLine
+		if requireCRLF {
+			readLine = w.readCRLFLine
+		}
+		line, start, err := readLine(ErrMalformedHeader)
+		if err == io.EOF {
+			return Headers{}, &ParseError{Offset: start, Err: io.ErrUnexpectedEOF}
+		}
+		if err != nil {
+			return Headers{}, err
+		}
+		folded := line != "" && (line[0] == ' ' || line[0] == '\t')
+		if !folded && lastName != "" {
+			headers = headers.WithAddedHeader(lastName, lastValue.String())
+			lastName = ""
+		}
+		if line == "" {
+			return headers, nil
+		}
+		count++
+		if w.limits.MaxHeaderCount >= 0 && count > w.limits.MaxHeaderCount {
+			return Headers{}, parseErrorf(start, ErrTooManyHeaders, "more than %d field lines", w.limits.MaxHeaderCount)
+		}
+		if i := strings.IndexByte(line, 0); i >= 0 {
+			return Headers{}, parseErrorf(start+int64(i), ErrMalformedHeader, "NUL character in field line")
+		}
+		if folded {
+			if isRequest || lastName == "" {
+				return Headers{}, parseErrorf(start, ErrMalformedHeader, "obsolete line folding is not allowed")
+			}
+			if folded := strings.Trim(line, " \t"); folded != "" {
+				if lastValue.Len() > 0 {
+					lastValue.WriteByte(' ')
+				}
+				lastValue.WriteString(folded)
+			}
+			continue
+		}
+		colon := strings.IndexByte(line, ':')
+		if colon < 0 {
+			return Headers{}, parseErrorf(start, ErrMalformedHeader, "missing colon")
+		}
+		name := line[:colon]
+		if err := validate(validateHeaderName(name)); err != nil {
+			return Headers{}, parseErrorf(start, ErrMalformedHeader, "%v", err)
+		}
+		lastName = name
+		lastValue.Reset()
+		lastValue.WriteString(strings.Trim(line[colon+1:], " \t"))
+	}
+}
+
+// readBody reads the body as framed by the Content-Length and Transfer-Encoding headers. A body with chunked transfer
+// coding is returned as a ChunkedReadableStream, so the trailers are available from the body. Transfer codings are
+// not defined for HTTP/1.0, so a HTTP/1.0 message with Transfer-Encoding is rejected as a smuggling attempt.
+//
+// See https://datatracker.ietf.org/doc/html/rfc9112#section-6.3 for details.
+func (w *wireReader) readBody(
+	headers Headers,
+	protocolVersion Version,
+	isRequest bool,
+	noBody bool,
+) (ReadableStream, error) {
+	if noBody {
+		return NewReadableStream(nil), nil
+	}
+	offset := w.offset
+	if headers.HasHeader("Transfer-Encoding") {
+		if protocolVersion.Minor() == 0 {
+			return nil, parseErrorf(
+				offset, ErrInvalidFraming, "a %s message must not contain Transfer-Encoding", protocolVersion,
+			)
+		}
+		if isRequest && headers.HasHeader("Content-Length") {
+			return nil, parseErrorf(
+				offset, ErrInvalidFraming, "a request must not contain both Content-Length and Transfer-Encoding",
+			)
+		}
+		var codings []string
+		for _, value := range headers.GetHeader("Transfer-Encoding") {
+			for _, coding := range strings.Split(value, ",") {
+				if coding = strings.ToLower(strings.Trim(coding, " \t")); coding != "" {
+					codings = append(codings, coding)
+				}
+			}
+		}
+		for i, coding := range codings {
+			if coding == "chunked" && i != len(codings)-1 {
+				return nil, parseErrorf(offset, ErrInvalidFraming, "chunked must be the final transfer coding")
+			}
+		}
+		if len(codings) > 0 && codings[len(codings)-1] == "chunked" {
+			return w.readChunkedBody()
+		}
+		if isRequest {
+			return nil, parseErrorf(offset, ErrInvalidFraming, "the final transfer coding of a request must be chunked")
+		}
+		return readableStreamOf(w.readBodyUntilEOF())
+	}
+	if headers.HasHeader("Content-Length") {
+		length, err := parseContentLength(headers.GetHeader("Content-Length"))
+		if err != nil {
+			return nil, &ParseError{Offset: offset, Err: err}
+		}
+		return readableStreamOf(w.readFixedBody(length))
+	}
+	if isRequest {
+		return NewReadableStream(nil), nil
+	}
+	return readableStreamOf(w.readBodyUntilEOF())
+}
+
+func readableStreamOf(body []byte, err error) (ReadableStream, error) {
+	if err != nil {
+		return nil, err
+	}
+	return NewReadableStream(body), nil
+}
+
+// parseContentLength parses the values of the Content-Length header. A list of identical values is accepted, since
+// some intermediaries combine duplicate headers.
+func parseContentLength(values []string) (int64, error) {
+	length := int64(-1)
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			item = strings.Trim(item, " \t")
+			n, ok := parseDecimal(item)
+			if !ok {
+				return 0, fmt.Errorf("%w: invalid Content-Length %q", ErrInvalidFraming, value)
+			}
+			if length >= 0 && n != length {
+				return 0, fmt.Errorf("%w: conflicting Content-Length values", ErrInvalidFraming)
+			}
+			length = n
+		}
+	}
+	return length, nil
+}
+
+// parseDecimal parses a non-empty string of decimal digits. False is returned for other characters and overflows.
+func parseDecimal(s string) (int64, bool) {
+	if s == "" {
+		return 0, false
+	}
+	n := int64(0)
+	for i := 0; i < len(s); i++ {
+		if !isDIGIT(s[i]) || n > (1<<63-1-int64(s[i]-'0'))/10 {
+			return 0, false
+		}
+		n = n*10 + int64(s[i]-'0')
+	}
+	return n, true
+}
+
+func (w *wireReader) checkBodySize(size int64) error {
+	if w.limits.MaxBodySize >= 0 && size > w.limits.MaxBodySize {
+		return parseErrorf(w.offset, ErrBodyTooLarge, "the body exceeds %d bytes", w.limits.MaxBodySize)
+	}
+	return nil
+}
+
+func (w *wireReader) readFixedBody(length int64) ([]byte, error) {
+	if err := w.checkBodySize(length); err != nil {
+		return nil, err
+	}
+	// The length is sent by the peer, so the buffer only grows as the body is actually received.
+	var body bytes.Buffer
+	n, err := io.CopyN(&body, w.r, length)
+	w.offset += n
+	if err != nil {
+		return nil, &ParseError{Offset: w.offset, Err: io.ErrUnexpectedEOF}
+	}
+	return body.Bytes(), nil
+}
+
+func (w *wireReader) readBodyUntilEOF() ([]byte, error) {
+	var source io.Reader = w.r
+	if w.limits.MaxBodySize >= 0 {
+		source = io.LimitReader(w.r, w.limits.MaxBodySize+1)
+	}
+	body, err := io.ReadAll(source)
+	if err != nil {
+		return nil, &ParseError{Offset: w.offset + int64(len(body)), Err: err}
+	}
+	if err := w.checkBodySize(int64(len(body))); err != nil {
+		return nil, err
+	}
+	w.offset += int64(len(body))
+	return body, nil
+}
+
+// readChunkedBody removes the chunked transfer coding. The body is decoded completely, so the reader is left at the
+// start of the next message.
+func (w *wireReader) readChunkedBody() (ReadableStream, error) {
+	stream := newChunkedReadableStream(w)
+	if err := stream.decodeAll(); err != nil {
+		return nil, err
+	}
+	return stream, nil
+}
+
+// tokenLength returns the length of the token at the start of s.
+func tokenLength(s string) int {
+	for i := 0; i < len(s); i++ {
+		if !isTokenChar(s[i]) {
+			return i
+		}
+	}
+	return len(s)
+}
+
+// parseQuotedString parses the quoted string at the start of s and returns its unescaped content and the remaining
+// input.
+//
+// See https://datatracker.ietf.org/doc/html/rfc9110#section-5.6.4 for details.
+func parseQuotedString(s string) (string, string, bool) {
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '"':
+			return b.String(), s[i+1:], true
+		case c == '\\' && i+1 < len(s) && (s[i+1] == '\t' || s[i+1] >= ' ') && s[i+1] != 0x7f:
+			i++
+			b.WriteByte(s[i])
+		case c == '\t' || (c >= ' ' && c != 0x7f && c != '\\'):
+			b.WriteByte(c)
+		default:
+			return "", "", false
+		}
+	}
+	return "", "", false
+}
+
+// parseWireVersion parses the HTTP-version of a start line, which must be exactly "HTTP/" followed by a digit, a dot
+// and a digit. Only HTTP/1.x is supported.
+func parseWireVersion(s string) (Version, error) {
+	if len(s) != 8 || !strings.HasPrefix(s, "HTTP/") || !isDIGIT(s[5]) || s[6] != '.' || !isDIGIT(s[7]) {
+		return nil, fmt.Errorf("%w: invalid HTTP version %q", ErrMalformedStartLine, s)
+	}
+	if s[5] != '1' {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedVersion, s)
+	}
+	return NewVersionE(s[5]-'0', s[7]-'0')
+}
+
+// baseURIFromHost builds the base URI for resolving the request target from the Host header.
+//
+// See https://datatracker.ietf.org/doc/html/rfc9112#section-3.2 for details.
+func baseURIFromHost(headers Headers, protocolVersion Version) (URI, error) {
+	hosts := headers.GetHeader("Host")
+	if len(hosts) > 1 || (len(hosts) == 0 && protocolVersion.Minor() >= 1) {
+		return nil, fmt.Errorf("%w: a HTTP/1.1 request must contain exactly one Host header", ErrMalformedHeader)
+	}
+	if len(hosts) == 0 || hosts[0] == "" {
+		return &uri{scheme: "http"}, nil
+	}
+	base, err := ParseURIE("http://" + hosts[0])
+	if err != nil || strings.ContainsAny(hosts[0], "/?#@") {
+		return nil, fmt.Errorf("%w: invalid Host header %q", ErrMalformedHeader, hosts[0])
+	}
+	return base, nil
+}
+
+//endregion
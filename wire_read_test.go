@@ -0,0 +1,400 @@
+package gsr7_test
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"go.debugged.it/gsr7"
+)
+
+//region Examples
+
+func ExampleReadServerRequest() {
+	input := "POST /submit?draft=1 HTTP/1.1\r\n" +
+		"Host: example.com\r\n" +
+		"Content-Length: 11\r\n" +
+		"\r\n" +
+		"hello world"
+	request, err := gsr7.ReadServerRequest(bufio.NewReader(strings.NewReader(input)), gsr7.DefaultParseLimits())
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(request.GetMethod(), request.GetURI(), request.GetProtocolVersion())
+	fmt.Println(request.GetBody().String())
+	// Output: POST http://example.com/submit?draft=1 HTTP/1.1
+	// hello world
+}
+
+func ExampleReadClientResponse() {
+	input := "HTTP/1.1 200 OK\r\n" +
+		"Transfer-Encoding: chunked\r\n" +
+		"\r\n" +
+		"5\r\nhello\r\n" +
+		"6;ext=1\r\n world\r\n" +
+		"0\r\n\r\n"
+	response, err := gsr7.ReadClientResponse(bufio.NewReader(strings.NewReader(input)), "GET", gsr7.ParseLimits{})
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(response.GetStatusCode(), response.GetReasonPhrase(), response.GetBody().String())
+	// Output: 200 OK hello world
+}
+
+//endregion
+
+//region Tests
+
+func TestReadServerRequest(t *testing.T) {
+	testData := []struct {
+		name    string
+		input   string
+		method  string
+		target  string
+		uri     string
+		version string
+		body    string
+	}{
+		{
+			"origin-form",
+			"GET /a/b?c HTTP/1.1\r\nHost: example.com:8080\r\nAccept: */*\r\n\r\n",
+			"GET", "/a/b?c", "http://example.com:8080/a/b?c", "HTTP/1.1", "",
+		},
+		{
+			"leading empty line and bare LF",
+			"\r\nGET / HTTP/1.1\nHost: example.com\n\n",
+			"GET", "/", "http://example.com/", "HTTP/1.1", "",
+		},
+		{
+			"absolute-form",
+			"GET http://other.example.com/x HTTP/1.1\r\nHost: example.com\r\n\r\n",
+			"GET", "http://other.example.com/x", "http://other.example.com/x", "HTTP/1.1", "",
+		},
+		{
+			"authority-form",
+			"CONNECT example.com:443 HTTP/1.1\r\nHost: example.com:443\r\n\r\n",
+			"CONNECT", "example.com:443", "http://example.com:443", "HTTP/1.1", "",
+		},
+		{
+			"asterisk-form",
+			"OPTIONS * HTTP/1.1\r\nHost: example.com\r\n\r\n",
+			"OPTIONS", "*", "http://example.com", "HTTP/1.1", "",
+		},
+		{
+			"HTTP/1.0 without Host",
+			"GET /index.html HTTP/1.0\r\n\r\n",
+			"GET", "/index.html", "http:/index.html", "HTTP/1.0", "",
+		},
+		{
+			"Content-Length",
+			"PUT /file HTTP/1.1\r\nHost: example.com\r\nContent-Length: 5, 5\r\n\r\nhello",
+			"PUT", "/file", "http://example.com/file", "HTTP/1.1", "hello",
+		},
+		{
+			"chunked with extensions and trailers",
+			"POST / HTTP/1.1\r\nHost: example.com\r\nTransfer-Encoding: gzip, Chunked\r\n\r\n" +
+				"3 ; a=b;c=\"d\\\"e\"\r\nabc\r\nA\r\n0123456789\r\n0\r\nDigest: x\r\n\r\n",
+			"POST", "/", "http://example.com/", "HTTP/1.1", "abc0123456789",
+		},
+	}
+	for _, testCase := range testData {
+		t.Run(
+			testCase.name, func(t *testing.T) {
+				reader := bufio.NewReader(strings.NewReader(testCase.input))
+				request, err := gsr7.ReadServerRequest(reader, gsr7.DefaultParseLimits())
+				if err != nil {
+					t.Fatalf("failed to read request (%v)", err)
+				}
+				assertEquals(t, request.GetMethod(), testCase.method, "incorrect method")
+				assertEquals(t, request.GetRequestTarget(), testCase.target, "incorrect request target")
+				assertEquals(t, request.GetURI().String(), testCase.uri, "incorrect URI")
+				assertEquals(t, request.GetProtocolVersion().String(), testCase.version, "incorrect version")
+				assertEquals(t, request.GetBody().String(), testCase.body, "incorrect body")
+				if _, err := gsr7.ReadServerRequest(reader, gsr7.DefaultParseLimits()); err != io.EOF {
+					t.Fatalf("the reader was not left at the end of the message (%v)", err)
+				}
+			},
+		)
+	}
+}
+
+func TestReadServerRequestPipelined(t *testing.T) {
+	input := "POST /a HTTP/1.1\r\nHost: example.com\r\nContent-Length: 3\r\n\r\nabc" +
+		"GET /b HTTP/1.1\r\nHost: example.com\r\nAccept: text/html\r\nAccept: text/plain\r\n\r\n"
+	reader := bufio.NewReader(strings.NewReader(input))
+	first, err := gsr7.ReadServerRequest(reader, gsr7.DefaultParseLimits())
+	if err != nil {
+		t.Fatalf("failed to read the first request (%v)", err)
+	}
+	assertEquals(t, first.GetBody().String(), "abc", "incorrect body")
+	second, err := gsr7.ReadServerRequest(reader, gsr7.DefaultParseLimits())
+	if err != nil {
+		t.Fatalf("failed to read the second request (%v)", err)
+	}
+	assertEquals(t, second.GetRequestTarget(), "/b", "incorrect request target")
+	assertEquals(t, second.GetHeaderLine("accept"), "text/html, text/plain", "incorrect header values")
+}
+
+func TestReadServerRequestTrailers(t *testing.T) {
+	input := "POST /a HTTP/1.1\r\nHost: example.com\r\nTrailer: Content-Digest\r\nTransfer-Encoding: chunked\r\n\r\n" +
+		"3\r\nabc\r\n0\r\nContent-Digest: sha-256=:abc=:\r\nContent-Length: 5\r\n\r\n"
+	request, err := gsr7.ReadServerRequest(bufio.NewReader(strings.NewReader(input)), gsr7.DefaultParseLimits())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertEquals(t, request.GetTrailers().Len(), 0, "trailers available before the body was read")
+	body, _ := io.ReadAll(request.GetBody())
+	assertEquals(t, string(body), "abc", "incorrect body")
+	trailers := request.GetTrailers()
+	assertEquals(t, trailers.GetHeaderLine("Content-Digest"), "sha-256=:abc=:", "incorrect trailer")
+	assertEquals(t, trailers.HasHeader("Content-Length"), false, "forbidden trailer not omitted")
+	assertEquals(t, request.GetHeaderLine("Content-Length"), "", "trailer merged into the headers")
+}
+
+func TestReadServerRequestErrors(t *testing.T) {
+	testData := []struct {
+		name   string
+		input  string
+		limits gsr7.ParseLimits
+		kind   error
+		offset int64
+	}{
+		{"missing version", "GET /\r\n\r\n", gsr7.ParseLimits{}, gsr7.ErrMalformedStartLine, 0},
+		{"double space", "GET  / HTTP/1.1\r\n\r\n", gsr7.ParseLimits{}, gsr7.ErrMalformedStartLine, 4},
+		{"lowercase version", "GET / http/1.1\r\n\r\n", gsr7.ParseLimits{}, gsr7.ErrMalformedStartLine, 6},
+		{"HTTP/2", "GET / HTTP/2.0\r\n\r\n", gsr7.ParseLimits{}, gsr7.ErrUnsupportedVersion, 6},
+		{"invalid method", "G(T / HTTP/1.1\r\n\r\n", gsr7.ParseLimits{}, gsr7.ErrMalformedStartLine, 0},
+		{"asterisk for GET", "GET * HTTP/1.1\r\nHost: a\r\n\r\n", gsr7.ParseLimits{}, gsr7.ErrMalformedStartLine, 4},
+		{"authority in path", "GET //evil.com/x HTTP/1.0\r\n\r\n", gsr7.ParseLimits{}, gsr7.ErrMalformedStartLine, 4},
+		{
+			"authority in path with empty Host", "GET //evil.com/x HTTP/1.1\r\nHost:\r\n\r\n",
+			gsr7.ParseLimits{}, gsr7.ErrMalformedStartLine, 4,
+		},
+		{"bare CR", "GET / HTTP/1.1\r\nHost: a\rb\r\n\r\n", gsr7.ParseLimits{}, gsr7.ErrMalformedHeader, 23},
+		{"missing Host", "GET / HTTP/1.1\r\nAccept: */*\r\n\r\n", gsr7.ParseLimits{}, gsr7.ErrMalformedHeader, 16},
+		{"two Host headers", "GET / HTTP/1.1\r\nHost: a\r\nHost: b\r\n\r\n", gsr7.ParseLimits{}, gsr7.ErrMalformedHeader, 16},
+		{"invalid Host", "GET / HTTP/1.1\r\nHost: a/b\r\n\r\n", gsr7.ParseLimits{}, gsr7.ErrMalformedHeader, 16},
+		{"space before colon", "GET / HTTP/1.1\r\nHost : a\r\n\r\n", gsr7.ParseLimits{}, gsr7.ErrMalformedHeader, 16},
+		{"missing colon", "GET / HTTP/1.1\r\nHost a\r\n\r\n", gsr7.ParseLimits{}, gsr7.ErrMalformedHeader, 16},
+		{
+			"obsolete line folding", "GET / HTTP/1.1\r\nHost: a\r\nX-A: b\r\n c\r\n\r\n",
+			gsr7.ParseLimits{}, gsr7.ErrMalformedHeader, 33,
+		},
+		{
+			"Content-Length and Transfer-Encoding",
+			"POST / HTTP/1.1\r\nHost: a\r\nContent-Length: 3\r\nTransfer-Encoding: chunked\r\n\r\n0\r\n\r\n",
+			gsr7.ParseLimits{}, gsr7.ErrInvalidFraming, 75,
+		},
+		{
+			"conflicting Content-Length", "POST / HTTP/1.1\r\nHost: a\r\nContent-Length: 3\r\nContent-Length: 4\r\n\r\nabcd",
+			gsr7.ParseLimits{}, gsr7.ErrInvalidFraming, 66,
+		},
+		{
+			"negative Content-Length", "POST / HTTP/1.1\r\nHost: a\r\nContent-Length: -1\r\n\r\n",
+			gsr7.ParseLimits{}, gsr7.ErrInvalidFraming, 48,
+		},
+		{
+			"overflowing Content-Length", "POST / HTTP/1.1\r\nHost: a\r\nContent-Length: 99999999999999999999\r\n\r\n",
+			gsr7.ParseLimits{}, gsr7.ErrInvalidFraming, 66,
+		},
+		{
+			"Transfer-Encoding with HTTP/1.0", "POST / HTTP/1.0\r\nTransfer-Encoding: chunked\r\n\r\n0\r\n\r\n",
+			gsr7.ParseLimits{}, gsr7.ErrInvalidFraming, 47,
+		},
+		{
+			"chunked not final", "POST / HTTP/1.1\r\nHost: a\r\nTransfer-Encoding: chunked, gzip\r\n\r\n",
+			gsr7.ParseLimits{}, gsr7.ErrInvalidFraming, 62,
+		},
+		{
+			"unknown transfer coding", "POST / HTTP/1.1\r\nHost: a\r\nTransfer-Encoding: gzip\r\n\r\n",
+			gsr7.ParseLimits{}, gsr7.ErrInvalidFraming, 53,
+		},
+		{
+			"invalid chunk size", "POST / HTTP/1.1\r\nHost: a\r\nTransfer-Encoding: chunked\r\n\r\n0x3\r\nabc\r\n0\r\n\r\n",
+			gsr7.ParseLimits{}, gsr7.ErrMalformedChunk, 56,
+		},
+		{
+			"overflowing chunk size",
+			"POST / HTTP/1.1\r\nHost: a\r\nTransfer-Encoding: chunked\r\n\r\n10000000000000000\r\n",
+			gsr7.ParseLimits{}, gsr7.ErrMalformedChunk, 56,
+		},
+		{
+			"invalid chunk extension", "POST / HTTP/1.1\r\nHost: a\r\nTransfer-Encoding: chunked\r\n\r\n3;=x\r\nabc\r\n",
+			gsr7.ParseLimits{}, gsr7.ErrMalformedChunk, 56,
+		},
+		{
+			"chunk longer than size", "POST / HTTP/1.1\r\nHost: a\r\nTransfer-Encoding: chunked\r\n\r\n3\r\nabcd\r\n",
+			gsr7.ParseLimits{}, gsr7.ErrMalformedChunk, 62,
+		},
+		{
+			"line too long", "GET /" + strings.Repeat("a", 100) + " HTTP/1.1\r\n\r\n",
+			gsr7.ParseLimits{MaxLineLength: 50}, gsr7.ErrLineTooLong, 50,
+		},
+		{
+			"too many headers", "GET / HTTP/1.1\r\nHost: a\r\nA: 1\r\nB: 2\r\n\r\n",
+			gsr7.ParseLimits{MaxHeaderCount: 2}, gsr7.ErrTooManyHeaders, 31,
+		},
+		{
+			"body too large", "POST / HTTP/1.1\r\nHost: a\r\nContent-Length: 11\r\n\r\nhello world",
+			gsr7.ParseLimits{MaxBodySize: 10}, gsr7.ErrBodyTooLarge, 48,
+		},
+		{
+			"chunked body too large",
+			"POST / HTTP/1.1\r\nHost: a\r\nTransfer-Encoding: chunked\r\n\r\n6\r\nhello \r\n5\r\nworld\r\n0\r\n\r\n",
+			gsr7.ParseLimits{MaxBodySize: 10}, gsr7.ErrBodyTooLarge, 67,
+		},
+		{"truncated headers", "GET / HTTP/1.1\r\nHost: a\r\n", gsr7.ParseLimits{}, io.ErrUnexpectedEOF, 25},
+		{
+			"truncated body", "POST / HTTP/1.1\r\nHost: a\r\nContent-Length: 10\r\n\r\nabc",
+			gsr7.ParseLimits{}, io.ErrUnexpectedEOF, 51,
+		},
+		{
+			"truncated body with maximum Content-Length",
+			"POST / HTTP/1.1\r\nHost: a\r\nContent-Length: 9223372036854775807\r\n\r\nabc",
+			gsr7.ParseLimits{MaxBodySize: -1}, io.ErrUnexpectedEOF, 68,
+		},
+	}
+	for _, testCase := range testData {
+		t.Run(
+			testCase.name, func(t *testing.T) {
+				_, err := gsr7.ReadServerRequest(bufio.NewReader(strings.NewReader(testCase.input)), testCase.limits)
+				if err == nil {
+					t.Fatalf("the invalid request did not result in an error")
+				}
+				var parseError *gsr7.ParseError
+				if !errors.As(err, &parseError) {
+					t.Fatalf("incorrect error type: %T (%v)", err, err)
+				}
+				if !errors.Is(err, testCase.kind) {
+					t.Fatalf("incorrect error: %v", err)
+				}
+				assertEquals(t, parseError.Offset, testCase.offset, "incorrect offset (%v)", err)
+			},
+		)
+	}
+}
+
+func TestReadClientResponse(t *testing.T) {
+	testData := []struct {
+		name          string
+		input         string
+		requestMethod string
+		code          uint16
+		reasonPhrase  string
+		body          string
+	}{
+		{"Content-Length", "HTTP/1.1 200 OK\r\nContent-Length: 2\r\n\r\nokextra", "GET", 200, "OK", "ok"},
+		{"until EOF", "HTTP/1.0 200 OK\r\n\r\nall of it", "GET", 200, "OK", "all of it"},
+		{"no reason phrase", "HTTP/1.1 404\r\nContent-Length: 0\r\n\r\n", "GET", 404, "", ""},
+		{"custom reason phrase", "HTTP/1.1 200 Fine, thanks\r\nContent-Length: 0\r\n\r\n", "GET", 200, "Fine, thanks", ""},
+		{"HEAD", "HTTP/1.1 200 OK\r\nContent-Length: 100\r\n\r\n", "HEAD", 200, "OK", ""},
+		{"204", "HTTP/1.1 204 No Content\r\n\r\n", "DELETE", 204, "No Content", ""},
+		{"304", "HTTP/1.1 304 Not Modified\r\nContent-Length: 100\r\n\r\n", "GET", 304, "Not Modified", ""},
+		{"interim", "HTTP/1.1 100 Continue\r\n\r\n", "POST", 100, "Continue", ""},
+		{"CONNECT", "HTTP/1.1 200 Connection established\r\n\r\n", "CONNECT", 200, "Connection established", ""},
+		{
+			"chunked", "HTTP/1.1 200 OK\r\nTransfer-Encoding: chunked\r\nContent-Length: 1\r\n\r\n2\r\nok\r\n0\r\n\r\n",
+			"GET", 200, "OK", "ok",
+		},
+		{"gzip until EOF", "HTTP/1.1 200 OK\r\nTransfer-Encoding: gzip\r\n\r\nraw", "GET", 200, "OK", "raw"},
+		{
+			"obsolete line folding", "HTTP/1.1 200 OK\r\nX-Folded: a\r\n  b\r\nContent-Length: 0\r\n\r\n",
+			"GET", 200, "OK", "",
+		},
+	}
+	for _, testCase := range testData {
+		t.Run(
+			testCase.name, func(t *testing.T) {
+				reader := bufio.NewReader(strings.NewReader(testCase.input))
+				response, err := gsr7.ReadClientResponse(reader, testCase.requestMethod, gsr7.DefaultParseLimits())
+				if err != nil {
+					t.Fatalf("failed to read response (%v)", err)
+				}
+				assertEquals(t, response.GetStatusCode(), testCase.code, "incorrect status code")
+				assertEquals(t, response.GetReasonPhrase(), testCase.reasonPhrase, "incorrect reason phrase")
+				assertEquals(t, response.GetBody().String(), testCase.body, "incorrect body")
+			},
+		)
+	}
+
+	input := "HTTP/1.1 200 OK\r\nX-Folded: a\r\n  b\r\nSet-Cookie: a=1\r\nSet-Cookie: b=2\r\n\r\n"
+	response, err := gsr7.ReadClientResponse(bufio.NewReader(strings.NewReader(input)), "HEAD", gsr7.DefaultParseLimits())
+	if err != nil {
+		t.Fatalf("failed to read response (%v)", err)
+	}
+	assertEquals(t, response.GetHeaderLine("X-Folded"), "a b", "obsolete line folding not replaced")
+	assertEquals(t, len(response.GetCookies()), 2, "incorrect number of cookies")
+}
+
+func TestReadClientResponseTrailers(t *testing.T) {
+	input := "HTTP/1.1 200 OK\r\nTrailer: Grpc-Status\r\nTransfer-Encoding: chunked\r\n\r\n" +
+		"2\r\nok\r\n0\r\nGrpc-Status: 0\r\n\r\n"
+	reader := bufio.NewReader(strings.NewReader(input))
+	response, err := gsr7.ReadClientResponse(reader, "GET", gsr7.DefaultParseLimits())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertEquals(t, response.GetBody().String(), "ok", "incorrect body")
+	assertEquals(t, response.GetTrailers().GetHeaderLine("Grpc-Status"), "0", "incorrect trailer")
+
+	modified := response.WithTrailer("Grpc-Message", "done").WithHeader("X-Test", "1")
+	assertEquals(t, modified.GetTrailers().GetHeaderLine("Grpc-Status"), "0", "trailer of the body not copied")
+	assertEquals(t, modified.GetTrailers().GetHeaderLine("Grpc-Message"), "done", "trailer not set")
+	assertEquals(t, response.GetTrailers().HasHeader("Grpc-Message"), false, "original response modified")
+
+	replaced := modified.WithBody(gsr7.NewReadableStream([]byte("other")))
+	assertEquals(t, replaced.GetTrailers().HasHeader("Grpc-Status"), false, "trailer of the replaced body kept")
+	assertEquals(t, replaced.GetTrailers().GetHeaderLine("Grpc-Message"), "done", "trailer not copied by WithBody")
+
+	if _, err := response.WithTrailerE("Set-Cookie", "a=b"); err == nil {
+		t.Fatalf("the forbidden trailer Set-Cookie did not result in an error")
+	}
+}
+
+func TestReadClientResponseErrors(t *testing.T) {
+	testData := []struct {
+		name   string
+		input  string
+		kind   error
+		offset int64
+	}{
+		{"short", "HTTP/1.1 20\r\n\r\n", gsr7.ErrMalformedStartLine, 0},
+		{"missing space", "HTTP/1.1 200OK\r\n\r\n", gsr7.ErrMalformedStartLine, 0},
+		{"invalid code", "HTTP/1.1 2x0 OK\r\n\r\n", gsr7.ErrMalformedStartLine, 10},
+		{"code out of range", "HTTP/1.1 600 Too High\r\n\r\n", gsr7.ErrMalformedStartLine, 9},
+		{"HTTP/0.9", "HTTP/0.9 200 OK\r\n\r\n", gsr7.ErrUnsupportedVersion, 0},
+		{"truncated chunk", "HTTP/1.1 200 OK\r\nTransfer-Encoding: chunked\r\n\r\n5\r\nab", io.ErrUnexpectedEOF, 52},
+		{
+			"too many folded lines", "HTTP/1.1 200 OK\r\nX-Folded: a\r\n" + strings.Repeat(" b\r\n", 100) + "\r\n",
+			gsr7.ErrTooManyHeaders, 426,
+		},
+		{"NUL in folded line", "HTTP/1.1 200 OK\r\nX-Folded: a\r\n b\x00\r\n\r\n", gsr7.ErrMalformedHeader, 32},
+		{
+			"Transfer-Encoding with HTTP/1.0", "HTTP/1.0 200 OK\r\nTransfer-Encoding: chunked\r\n\r\n0\r\n\r\n",
+			gsr7.ErrInvalidFraming, 47,
+		},
+	}
+	for _, testCase := range testData {
+		t.Run(
+			testCase.name, func(t *testing.T) {
+				reader := bufio.NewReader(strings.NewReader(testCase.input))
+				_, err := gsr7.ReadClientResponse(reader, "GET", gsr7.DefaultParseLimits())
+				var parseError *gsr7.ParseError
+				if !errors.As(err, &parseError) || !errors.Is(err, testCase.kind) {
+					t.Fatalf("incorrect error: %v", err)
+				}
+				assertEquals(t, parseError.Offset, testCase.offset, "incorrect offset (%v)", err)
+			},
+		)
+	}
+
+	_, err := gsr7.ReadClientResponse(bufio.NewReader(strings.NewReader("")), "GET", gsr7.ParseLimits{})
+	if err != io.EOF {
+		t.Fatalf("empty input did not result in io.EOF (%v)", err)
+	}
+}
+
+//endregion
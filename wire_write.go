@@ -0,0 +1,265 @@
+package gsr7
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+//region Interface
+
+// WriteClientRequest serializes the request in the HTTP/1.x wire format described in RFC 9112. The request line is
+// built from the method, GetRequestTarget and GetProtocolVersion, followed by every header value on a separate
+// field line. Obsolete line folding is never generated.
+//
+// The body framing is chosen as follows:
+//
+//   - If the request has a Transfer-Encoding header ending in chunked, the body is sent with chunked coding.
+//   - If the request has a Content-Length header, the body must have exactly that length.
+//   - If the length of the body is known, because it is nil or a WritableBuffer, a Content-Length header is added.
+//     Empty bodies only get one for POST, PUT and PATCH requests.
+//   - If the body can only be read as an io.Reader, or the request has trailers, it is sent with chunked coding,
+//     which requires HTTP/1.1. The trailers from GetTrailers are written after the last chunk.
+//
+// An error is returned if the protocol version is not HTTP/1.x, if a HTTP/1.1 request has no Host header, if a
+// header name or value is invalid, if the body cannot be read, or if the headers describe conflicting or unsupported
+// framing, including trailers without chunked coding.
+//
+// See https://datatracker.ietf.org/doc/html/rfc9112 for details.
+func WriteClientRequest(w io.Writer, req ClientRequest) error {
+	protocolVersion := req.GetProtocolVersion()
+	if protocolVersion.Major() != 1 {
+		return fmt.Errorf("cannot write %s request in the HTTP/1.x format", protocolVersion)
+	}
+	if protocolVersion.Minor() >= 1 && req.GetHeaderLine("Host") == "" {
+		return fmt.Errorf("a %s request must contain a Host header", protocolVersion)
+	}
+	headers, err := toHeaders(req.GetHeaders())
+	if err != nil {
+		return err
+	}
+	body, err := newOutgoingBody(req.GetBody())
+	if err != nil {
+		return err
+	}
+	method := req.GetMethod()
+	m := outgoingMessage{
+		startLine:       method + " " + req.GetRequestTarget() + " " + protocolVersion.String(),
+		protocolVersion: protocolVersion,
+		headers:         headers,
+		body:            body,
+		trailers:        req.GetTrailers(),
+		isRequest:       true,
+		emptyWithLength: method == "POST" || method == "PUT" || method == "PATCH",
+	}
+	return m.write(w)
+}
+
+// WriteServerResponse serializes the response in the HTTP/1.x wire format described in RFC 9112. The status line is
+// built from GetProtocolVersion, the status code and the reason phrase, followed by every header value on a
+// separate field line. Obsolete line folding is never generated.
+//
+// The body framing is chosen as in WriteClientRequest, with two differences: responses with the status codes 1xx,
+// 204 and 304 never have a body, and a HTTP/1.0 response with a body of unknown length is delimited by closing the
+// connection, so the caller must close it after writing.
+//
+// See https://datatracker.ietf.org/doc/html/rfc9112 for details.
+func WriteServerResponse(w io.Writer, resp ServerResponse) error {
+	protocolVersion := resp.GetProtocolVersion()
+	if protocolVersion.Major() != 1 {
+		return fmt.Errorf("cannot write %s response in the HTTP/1.x format", protocolVersion)
+	}
+	code := resp.GetStatusCode()
+	headers, err := toHeaders(resp.GetHeaders())
+	if err != nil {
+		return err
+	}
+	body, err := newOutgoingBody(resp.GetBody())
+	if err != nil {
+		return err
+	}
+	m := outgoingMessage{
+		startLine:       fmt.Sprintf("%s %03d %s", protocolVersion, code, resp.GetReasonPhrase()),
+		protocolVersion: protocolVersion,
+		headers:         headers,
+		body:            body,
+		trailers:        resp.GetTrailers(),
+		noBody:          code < 200 || code == 204 || code == 304,
+		emptyWithLength: true,
+	}
+	return m.write(w)
+}
+
+//endregion
+
+//region Implementation
+
+// outgoingBody is the content of a WritableStream set as the body of a message that is being sent.
+type outgoingBody struct {
+	// known is true if the body is available in memory in data. Otherwise, it is read from reader.
+	known  bool
+	data   []byte
+	reader io.Reader
+}
+
+func newOutgoingBody(body WritableStream) (outgoingBody, error) {
+	if body == nil {
+		return outgoingBody{known: true}, nil
+	}
+	switch b := body.(type) {
+	case interface{ Bytes() []byte }:
+		return outgoingBody{known: true, data: b.Bytes()}, nil
+	case io.Reader:
+		return outgoingBody{reader: b}, nil
+	}
+	return outgoingBody{}, fmt.Errorf("the body of type %T cannot be read", body)
+}
+
+// toHeaders converts the result of GetHeaders back into a header collection.
+func toHeaders(fields [][]string) (Headers, error) {
+	headers := NewHeaders()
+	for _, field := range fields {
+		var err error
+		if headers, err = headers.WithHeaderValuesE(field[0], field[1:]); err != nil {
+			return Headers{}, err
+		}
+	}
+	return headers, nil
+}
+
+type outgoingMessage struct {
+	startLine       string
+	protocolVersion Version
+	headers         Headers
+	body            outgoingBody
+	trailers        Headers
+	isRequest       bool
+	// noBody is true if the message cannot have a body, regardless of its headers.
+	noBody bool
+	// emptyWithLength is true if an empty body is announced with Content-Length: 0.
+	emptyWithLength bool
+}
+
+// framing determines how the body is delimited and adds the Content-Length or Transfer-Encoding header if needed.
+// The length is -1 if the body is not delimited by Content-Length.
+func (m *outgoingMessage) framing() (chunked bool, length int64, err error) {
+	hasTransferEncoding := m.headers.HasHeader("Transfer-Encoding")
+	hasContentLength := m.headers.HasHeader("Content-Length")
+	if m.noBody {
+		if m.trailers.Len() > 0 {
+			return false, -1, fmt.Errorf("a message without a body cannot have trailers")
+		}
+		return false, -1, nil
+	}
+	chunked, length, err = m.bodyFraming(hasTransferEncoding, hasContentLength)
+	if err == nil && !chunked && m.trailers.Len() > 0 {
+		return false, -1, fmt.Errorf("trailers can only be sent with chunked transfer coding")
+	}
+	return chunked, length, err
+}
+
+// bodyFraming implements framing for messages that can have a body.
+func (m *outgoingMessage) bodyFraming(hasTransferEncoding, hasContentLength bool) (bool, int64, error) {
+	switch {
+	case hasTransferEncoding && hasContentLength:
+		return false, -1, fmt.Errorf("a message must not contain both Content-Length and Transfer-Encoding")
+	case hasTransferEncoding:
+		if m.protocolVersion.Minor() < 1 {
+			return false, -1, fmt.Errorf("transfer codings require HTTP/1.1")
+		}
+		codings := strings.Split(m.headers.GetHeaderLine("Transfer-Encoding"), ",")
+		if strings.EqualFold(strings.Trim(codings[len(codings)-1], " \t"), "chunked") {
+			return true, -1, nil
+		}
+		if m.isRequest {
+			return false, -1, fmt.Errorf("the final transfer coding of a request must be chunked")
+		}
+		return false, -1, nil
+	case hasContentLength:
+		length, err := parseContentLength(m.headers.GetHeader("Content-Length"))
+		if err != nil {
+			return false, -1, err
+		}
+		if m.body.known && int64(len(m.body.data)) != length {
+			return false, -1, fmt.Errorf(
+				"the body has %d bytes, but the Content-Length header announces %d", len(m.body.data), length,
+			)
+		}
+		return false, length, nil
+	case m.body.known && (m.trailers.Len() == 0 || m.protocolVersion.Minor() < 1):
+		if len(m.body.data) > 0 || m.emptyWithLength {
+			m.headers = m.headers.WithHeader("Content-Length", strconv.Itoa(len(m.body.data)))
+		}
+		return false, int64(len(m.body.data)), nil
+	case m.protocolVersion.Minor() >= 1:
+		m.headers = m.headers.WithHeader("Transfer-Encoding", "chunked")
+		return true, -1, nil
+	case m.isRequest:
+		return false, -1, fmt.Errorf("a HTTP/1.0 request with a body of unknown length cannot be sent")
+	default:
+		return false, -1, nil
+	}
+}
+
+func (m outgoingMessage) write(w io.Writer) error {
+	chunked, length, err := m.framing()
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	_, _ = bw.WriteString(m.startLine)
+	_, _ = bw.WriteString("\r\n")
+	writeFields(bw, m.headers)
+	_, _ = bw.WriteString("\r\n")
+
+	if !m.noBody {
+		switch {
+		case chunked:
+			err = writeChunked(bw, m.body, m.trailers)
+		case m.body.known:
+			_, err = bw.Write(m.body.data)
+		case length >= 0:
+			var n int64
+			n, err = io.CopyN(bw, m.body.reader, length)
+			if err == io.EOF {
+				err = fmt.Errorf("the body has %d bytes, but the Content-Length header announces %d", n, length)
+			}
+		default:
+			_, err = io.Copy(bw, m.body.reader)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// writeFields writes every value of the headers on a separate field line. Header values cannot contain line breaks,
+// so no obsolete line folding is generated.
+func writeFields(bw *bufio.Writer, headers Headers) {
+	for _, field := range headers.GetHeaders() {
+		for _, value := range field[1:] {
+			_, _ = bw.WriteString(field[0])
+			_, _ = bw.WriteString(": ")
+			_, _ = bw.WriteString(value)
+			_, _ = bw.WriteString("\r\n")
+		}
+	}
+}
+
+// writeChunked writes the body with chunked transfer coding, followed by the trailer section.
+func writeChunked(bw *bufio.Writer, body outgoingBody, trailers Headers) error {
+	stream := NewChunkedWritableStream(bw)
+	if body.known {
+		if _, err := stream.Write(body.data); err != nil {
+			return err
+		}
+	} else if _, err := io.CopyBuffer(stream, body.reader, make([]byte, chunkedReadSize)); err != nil {
+		return err
+	}
+	return stream.CloseWithTrailers(trailers)
+}
+
+//endregion
@@ -0,0 +1,292 @@
+package gsr7_test
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"go.debugged.it/gsr7"
+)
+
+//region Examples
+
+func ExampleWriteClientRequest() {
+	body := gsr7.NewWritableBuffer()
+	_, _ = body.Write([]byte("hello world"))
+	request := gsr7.NewClientRequest("POST", gsr7.ParseURI("http://example.com/submit")).WithBody(body)
+	var output bytes.Buffer
+	if err := gsr7.WriteClientRequest(&output, request); err != nil {
+		panic(err)
+	}
+	fmt.Printf("%q\n", output.String())
+	// Output: "POST /submit HTTP/1.1\r\nHost: example.com\r\nContent-Length: 11\r\n\r\nhello world"
+}
+
+func ExampleWriteServerResponse() {
+	response := gsr7.NewServerResponse(404).WithHeader("Content-Type", "text/plain")
+	var output bytes.Buffer
+	if err := gsr7.WriteServerResponse(&output, response); err != nil {
+		panic(err)
+	}
+	fmt.Print(strings.ReplaceAll(output.String(), "\r\n", "\n"))
+	// Output: HTTP/1.1 404 Not Found
+	// Content-Type: text/plain
+	// Content-Length: 0
+}
+
+//endregion
+
+//region Tests
+
+// streamBody is a body that can only be read as a stream, so its length is unknown.
+type streamBody struct {
+	io.Reader
+}
+
+func (s streamBody) Write(p []byte) (int, error) {
+	return len(p), nil
+}
+
+func (s streamBody) Close() error {
+	return nil
+}
+
+func TestWriteClientRequest(t *testing.T) {
+	uri := gsr7.ParseURI("http://example.com/a?b")
+	buffer := func(data string) gsr7.WritableStream {
+		body := gsr7.NewWritableBuffer()
+		_, _ = body.Write([]byte(data))
+		return body
+	}
+	testData := []struct {
+		name     string
+		request  gsr7.ClientRequest
+		expected string
+	}{
+		{
+			"no body",
+			gsr7.NewClientRequest("GET", uri).WithAddedHeader("Accept", "text/html").WithAddedHeader("Accept", "*/*"),
+			"GET /a?b HTTP/1.1\r\nHost: example.com\r\nAccept: text/html\r\nAccept: */*\r\n\r\n",
+		},
+		{
+			"empty POST",
+			gsr7.NewClientRequest("POST", uri),
+			"POST /a?b HTTP/1.1\r\nHost: example.com\r\nContent-Length: 0\r\n\r\n",
+		},
+		{
+			"known length",
+			gsr7.NewClientRequest("PUT", uri).WithBody(buffer("abc")),
+			"PUT /a?b HTTP/1.1\r\nHost: example.com\r\nContent-Length: 3\r\n\r\nabc",
+		},
+		{
+			"unknown length",
+			gsr7.NewClientRequest("POST", uri).WithBody(streamBody{strings.NewReader("abc")}),
+			"POST /a?b HTTP/1.1\r\nHost: example.com\r\nTransfer-Encoding: chunked\r\n\r\n3\r\nabc\r\n0\r\n\r\n",
+		},
+		{
+			"explicit Content-Length",
+			gsr7.NewClientRequest("POST", uri).
+				WithHeader("Content-Length", "3").
+				WithBody(streamBody{strings.NewReader("abc")}),
+			"POST /a?b HTTP/1.1\r\nHost: example.com\r\nContent-Length: 3\r\n\r\nabc",
+		},
+		{
+			"explicit chunked",
+			gsr7.NewClientRequest("POST", uri).WithHeader("Transfer-Encoding", "chunked").WithBody(buffer("abc")),
+			"POST /a?b HTTP/1.1\r\nHost: example.com\r\nTransfer-Encoding: chunked\r\n\r\n3\r\nabc\r\n0\r\n\r\n",
+		},
+		{
+			"proxy request",
+			gsr7.NewClientRequest("GET", uri).WithRequestTarget(gsr7.NewRequestTarget("GET", uri, true)),
+			"GET http://example.com/a?b HTTP/1.1\r\nHost: example.com\r\n\r\n",
+		},
+		{
+			"HTTP/1.0",
+			gsr7.NewClientRequest("GET", uri).WithProtocolVersion(gsr7.HTTP10).WithoutHeader("Host"),
+			"GET /a?b HTTP/1.0\r\n\r\n",
+		},
+	}
+	for _, tc := range testData {
+		t.Run(
+			tc.name, func(t *testing.T) {
+				var output bytes.Buffer
+				if err := gsr7.WriteClientRequest(&output, tc.request); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				assertEquals(t, output.String(), tc.expected, "incorrect output")
+			},
+		)
+	}
+}
+
+func TestWriteClientRequestErrors(t *testing.T) {
+	uri := gsr7.ParseURI("http://example.com/")
+	testData := []struct {
+		name    string
+		request gsr7.ClientRequest
+	}{
+		{"HTTP/2", gsr7.NewClientRequest("GET", uri).WithProtocolVersion(gsr7.HTTP20)},
+		{"missing Host", gsr7.NewClientRequest("GET", uri).WithoutHeader("Host")},
+		{
+			"Content-Length and Transfer-Encoding",
+			gsr7.NewClientRequest("POST", uri).
+				WithHeader("Content-Length", "3").
+				WithHeader("Transfer-Encoding", "chunked"),
+		},
+		{"final coding not chunked", gsr7.NewClientRequest("POST", uri).WithHeader("Transfer-Encoding", "gzip")},
+		{"Content-Length mismatch", gsr7.NewClientRequest("POST", uri).WithHeader("Content-Length", "3")},
+		{
+			"short stream",
+			gsr7.NewClientRequest("POST", uri).
+				WithHeader("Content-Length", "5").
+				WithBody(streamBody{strings.NewReader("abc")}),
+		},
+		{
+			"unknown length with HTTP/1.0",
+			gsr7.NewClientRequest("POST", uri).
+				WithProtocolVersion(gsr7.HTTP10).
+				WithBody(streamBody{strings.NewReader("abc")}),
+		},
+	}
+	for _, tc := range testData {
+		t.Run(
+			tc.name, func(t *testing.T) {
+				if err := gsr7.WriteClientRequest(io.Discard, tc.request); err == nil {
+					t.Fatalf("no error returned")
+				}
+			},
+		)
+	}
+}
+
+func TestWriteServerResponse(t *testing.T) {
+	testData := []struct {
+		name     string
+		response gsr7.ServerResponse
+		expected string
+	}{
+		{
+			"no content",
+			gsr7.NewServerResponse(204).WithHeader("X-Test", "1"),
+			"HTTP/1.1 204 No Content\r\nX-Test: 1\r\n\r\n",
+		},
+		{
+			"not modified keeps Content-Length",
+			gsr7.NewServerResponse(304).WithHeader("Content-Length", "10"),
+			"HTTP/1.1 304 Not Modified\r\nContent-Length: 10\r\n\r\n",
+		},
+		{
+			"unknown length",
+			gsr7.NewServerResponse(200).WithBody(streamBody{strings.NewReader("hello")}),
+			"HTTP/1.1 200 OK\r\nTransfer-Encoding: chunked\r\n\r\n5\r\nhello\r\n0\r\n\r\n",
+		},
+		{
+			"HTTP/1.0 close-delimited",
+			gsr7.NewServerResponse(200).
+				WithProtocolVersion(gsr7.HTTP10).
+				WithBody(streamBody{strings.NewReader("hello")}),
+			"HTTP/1.0 200 OK\r\n\r\nhello",
+		},
+		{
+			"custom reason phrase",
+			gsr7.NewServerResponse(200).WithStatus(599, ""),
+			"HTTP/1.1 599 \r\nContent-Length: 0\r\n\r\n",
+		},
+	}
+	for _, tc := range testData {
+		t.Run(
+			tc.name, func(t *testing.T) {
+				var output bytes.Buffer
+				if err := gsr7.WriteServerResponse(&output, tc.response); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				assertEquals(t, output.String(), tc.expected, "incorrect output")
+			},
+		)
+	}
+}
+
+func TestWriteServerResponseTrailers(t *testing.T) {
+	body := gsr7.NewWritableBuffer()
+	_, _ = body.Write([]byte("hello"))
+	response := gsr7.NewServerResponse(200).
+		WithDeclaredTrailers("Grpc-Status").
+		WithTrailer("Grpc-Status", "0").
+		WithBody(body)
+	var output bytes.Buffer
+	if err := gsr7.WriteServerResponse(&output, response); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertEquals(
+		t, output.String(),
+		"HTTP/1.1 200 OK\r\nTrailer: Grpc-Status\r\nTransfer-Encoding: chunked\r\n\r\n"+
+			"5\r\nhello\r\n0\r\nGrpc-Status: 0\r\n\r\n",
+		"incorrect output",
+	)
+
+	parsed, err := gsr7.ReadClientResponse(bufio.NewReader(&output), "GET", gsr7.DefaultParseLimits())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertEquals(t, parsed.GetBody().String(), "hello", "incorrect body")
+	assertEquals(t, parsed.GetTrailers().GetHeaderLine("Grpc-Status"), "0", "incorrect trailer")
+
+	for _, invalid := range []gsr7.ServerResponse{
+		response.WithHeader("Content-Length", "5"),
+		response.WithProtocolVersion(gsr7.HTTP10),
+		response.WithStatusCode(204),
+	} {
+		if err := gsr7.WriteServerResponse(io.Discard, invalid); err == nil {
+			t.Fatalf("trailers without chunked transfer coding did not result in an error")
+		}
+	}
+}
+
+// invalidHeadersRequest and invalidHeadersResponse are foreign message implementations returning an invalid header.
+type invalidHeadersRequest struct {
+	gsr7.ClientRequest
+}
+
+func (r invalidHeadersRequest) GetHeaders() [][]string {
+	return [][]string{{"Host", "example.com"}, {"X-Test", "a\r\nX-Injected: 1"}}
+}
+
+type invalidHeadersResponse struct {
+	gsr7.ServerResponse
+}
+
+func (r invalidHeadersResponse) GetHeaders() [][]string {
+	return [][]string{{"Invalid Name", "1"}}
+}
+
+func TestWriteInvalidHeaders(t *testing.T) {
+	request := invalidHeadersRequest{gsr7.NewClientRequest("GET", gsr7.ParseURI("http://example.com/"))}
+	if err := gsr7.WriteClientRequest(io.Discard, request); err == nil {
+		t.Fatalf("an invalid request header did not result in an error")
+	}
+	response := invalidHeadersResponse{gsr7.NewServerResponse(200)}
+	if err := gsr7.WriteServerResponse(io.Discard, response); err == nil {
+		t.Fatalf("an invalid response header did not result in an error")
+	}
+}
+
+func TestWriteReadRoundTrip(t *testing.T) {
+	request := gsr7.NewClientRequest("POST", gsr7.ParseURI("http://example.com/upload")).
+		WithBody(streamBody{strings.NewReader(strings.Repeat("x", 100000))})
+	var output bytes.Buffer
+	if err := gsr7.WriteClientRequest(&output, request); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	parsed, err := gsr7.ReadServerRequest(bufio.NewReader(&output), gsr7.DefaultParseLimits())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertEquals(t, parsed.GetMethod(), "POST", "incorrect method")
+	assertEquals(t, parsed.GetURI().String(), "http://example.com/upload", "incorrect URI")
+	assertEquals(t, parsed.GetBody().String(), strings.Repeat("x", 100000), "incorrect body")
+}
+
+//endregion